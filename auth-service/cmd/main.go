@@ -1,7 +1,9 @@
 package main
 
 import (
+	"context"
 	"log"
+	"log/slog"
 	"net/http"
 	"os"
 	"os/signal"
@@ -9,72 +11,240 @@ import (
 	"time"
 
 	"github.com/natrayanp/GoMicro/auth-service/internal/adapters/grpc"
+	"github.com/natrayanp/GoMicro/auth-service/internal/adapters/metrics"
 	"github.com/natrayanp/GoMicro/auth-service/internal/api/health"
 	"github.com/natrayanp/GoMicro/auth-service/internal/auth/jwt"
+	"github.com/natrayanp/GoMicro/auth-service/internal/auth/opaque"
+	"github.com/natrayanp/GoMicro/auth-service/internal/auth/password"
 	"github.com/natrayanp/GoMicro/auth-service/internal/config"
 	"github.com/natrayanp/GoMicro/auth-service/internal/core"
+	"github.com/natrayanp/GoMicro/auth-service/internal/domain"
+	"github.com/natrayanp/GoMicro/auth-service/internal/logging"
+	"github.com/natrayanp/GoMicro/auth-service/internal/ports"
+	"github.com/natrayanp/GoMicro/auth-service/internal/ratelimit"
+	"github.com/natrayanp/GoMicro/auth-service/internal/storage/memory"
 	"github.com/natrayanp/GoMicro/auth-service/internal/storage/postgres"
+	"github.com/natrayanp/GoMicro/auth-service/internal/tokencache"
+	"github.com/natrayanp/GoMicro/auth-service/internal/tracing"
+
+	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 func main() {
 	// Load configuration
 	cfg := config.Load()
-	log.Println("Configuration loaded")
 
-	// Setup database connection
-	db, err := postgres.NewConnection(&cfg.Database)
+	// Every later log line, including from the standard log package, goes
+	// through the redacting structured logger
+	logger, err := logging.New(os.Stdout, &cfg.Log)
 	if err != nil {
-		log.Fatalf("Failed to connect to database: %v", err)
+		log.Fatalf("Failed to initialize logging: %v", err)
 	}
-	defer db.Close()
-	log.Println("Database connected")
+	slog.SetDefault(logger)
+	logger.Info("configuration loaded")
 
-	// Setup repositories (implement ports)
-	userRepo := postgres.NewUserRepository(db)
-	tokenRepo := postgres.NewTokenRepository(db)
+	// "auth-service migrate up|down|status" manages the schema and exits
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := runMigrate(cfg, os.Args[2:]); err != nil {
+			fatal("migration failed", "error", err)
+		}
+		return
+	}
 
-	// Setup JWT provider (implements TokenProviderPort)
-	jwtProvider := jwt.NewJWTProvider(
-		cfg.JWT.SecretKey,
-		cfg.JWT.AccessExpiry,
-		cfg.JWT.RefreshExpiry,
+	// Setup tracing before anything that creates spans
+	shutdownTracing, err := tracing.Setup(context.Background(), &cfg.Tracing)
+	if err != nil {
+		fatal("failed to initialize tracing", "error", err)
+	}
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := shutdownTracing(ctx); err != nil {
+			logger.Error("failed to flush traces", "error", err)
+		}
+	}()
+
+	// Setup storage (implements repository ports)
+	var (
+		userRepo        ports.UserRepository
+		tokenRepo       ports.TokenRepository
+		passwordHistory ports.PasswordHistoryRepository
+		loginAttempts   ports.LoginAttemptRepository
+		accessDenylist  ports.AccessTokenDenylistRepository
+		unitOfWork      ports.UnitOfWork
+		pool            *pgxpool.Pool
 	)
 
+	switch cfg.Database.Driver {
+	case "memory":
+		userRepo = memory.NewUserRepository()
+		tokenRepo = memory.NewTokenRepository()
+		passwordHistory = memory.NewPasswordHistoryRepository()
+		loginAttempts = memory.NewLoginAttemptRepository()
+		accessDenylist = memory.NewAccessTokenDenylistRepository()
+		unitOfWork = memory.NewUnitOfWork(ports.TxRepositories{
+			Users:           userRepo,
+			Tokens:          tokenRepo,
+			PasswordHistory: passwordHistory,
+		})
+		logger.Warn("using in-memory storage; data is lost on restart")
+
+	case "postgres":
+		db, err := postgres.NewConnection(&cfg.Database)
+		if err != nil {
+			fatal("failed to connect to database", "error", err)
+		}
+		defer db.Close()
+		logger.Info("database connected")
+
+		if cfg.Database.AutoMigrate {
+			if err := autoMigrate(db); err != nil {
+				fatal("failed to migrate database", "error", err)
+			}
+			logger.Info("database schema up to date")
+		}
+
+		userRepo = postgres.NewUserRepository(db)
+		tokenRepo = postgres.NewTokenRepository(db)
+		passwordHistory = postgres.NewPasswordHistoryRepository(db)
+		loginAttempts = postgres.NewLoginAttemptRepository(db)
+		accessDenylist = postgres.NewAccessTokenDenylistRepository(db)
+		unitOfWork = postgres.NewUnitOfWork(db)
+		pool = db.Pool
+
+	default:
+		fatal("unknown storage driver", "driver", cfg.Database.Driver)
+	}
+
+	// Setup JWT provider (implements TokenProviderPort)
+	jwtProvider, err := jwt.NewProviderFromConfig(&cfg.JWT)
+	if err != nil {
+		fatal("failed to initialize JWT provider", "error", err)
+	}
+
+	// Promote rotated signing keys without a restart
+	watchCtx, stopWatching := context.WithCancel(context.Background())
+	defer stopWatching()
+	go jwtProvider.WatchKeyFile(watchCtx, &cfg.JWT)
+
+	// Drop denylist entries once the tokens they cover have expired
+	go pruneAccessTokenDenylist(watchCtx, accessDenylist)
+
+	// Refresh tokens are either JWTs or opaque random strings
+	var tokenProvider ports.TokenProviderPort = jwtProvider
+	if cfg.JWT.RefreshFormat == "opaque" {
+		tokenProvider = opaque.NewProvider(jwtProvider, cfg.JWT.RefreshExpiry)
+	}
+
+	// Setup password hashing (implements PasswordHasher)
+	passwordHasher, err := password.NewHasherFromConfig(&cfg.Password)
+	if err != nil {
+		fatal("failed to initialize password hasher", "error", err)
+	}
+
+	// Setup password policy (implements PasswordPolicy)
+	passwordPolicy, err := password.NewPolicyFromConfig(&cfg.Password)
+	if err != nil {
+		fatal("failed to initialize password policy", "error", err)
+	}
+
+	// Setup metrics (implements MetricsRecorder, served on /metrics)
+	promMetrics := metrics.NewPrometheus()
+	if pool != nil {
+		promMetrics.RegisterPool(pool)
+	}
+
 	// Setup auth service (implements AuthServicePort)
 	// Note: eventPublisher is nil for now, can be added later
-	authService := core.NewAuthService(userRepo, tokenRepo, jwtProvider, nil)
-	log.Println("Core services initialized")
+	serviceOpts := []core.Option{
+		core.WithUnitOfWork(unitOfWork),
+		core.WithPasswordHasher(passwordHasher),
+		core.WithPasswordPolicy(passwordPolicy),
+		core.WithPasswordHistory(passwordHistory, cfg.Password.HistorySize),
+		core.WithLockout(loginAttempts, domain.LockoutPolicy{
+			MaxAttempts:  cfg.Lockout.MaxAttempts,
+			BaseDuration: cfg.Lockout.BaseDuration,
+			MaxDuration:  cfg.Lockout.MaxDuration,
+			ResetAfter:   cfg.Lockout.ResetAfter,
+		}),
+		core.WithMetrics(promMetrics),
+		core.WithAccessTokenDenylist(accessDenylist, cfg.JWT.AccessExpiry),
+		core.WithAdmins(cfg.Auth.AdminUserIDs),
+		core.WithScopes(cfg.Auth.TokenScopes),
+	}
+	if cfg.ValidationCache.Size > 0 {
+		serviceOpts = append(serviceOpts, core.WithValidationCache(tokencache.NewLRU(cfg.ValidationCache.Size, cfg.ValidationCache.TTL)))
+	}
+	authService := core.NewAuthService(userRepo, tokenRepo, tokenProvider, nil, serviceOpts...)
+	logger.Info("core services initialized")
 
 	// Setup gRPC handler (adapter)
 	grpcHandler := grpc.NewGrpcAuthHandler(authService)
 
+	// Setup rate limiting (in-memory buckets, per instance)
+	limiter, err := ratelimit.NewLimiterFromConfig(&cfg.RateLimit, ratelimit.NewMemoryStore())
+	if err != nil {
+		fatal("failed to initialize rate limiter", "error", err)
+	}
+
 	// Setup gRPC server
-	grpcServer := grpc.NewGrpcServer(cfg, grpcHandler)
+	grpcServer := grpc.NewGrpcServer(cfg, grpcHandler,
+		grpc.WithLogger(logger),
+		grpc.WithRateLimiter(limiter),
+		grpc.WithMetrics(promMetrics),
+	)
 	grpcServer.RegisterService()
 
 	// Setup health checks
-	healthChecker := health.NewHealthChecker(db.Pool)
+	healthChecker := health.NewHealthChecker(pool)
 
-	// Start health check HTTP server
-	go startHealthServer(healthChecker)
+	// Start health check HTTP server (also serves the JWKS document and metrics)
+	go startHealthServer(healthChecker, jwtProvider, promMetrics)
 
 	// Start gRPC server
 	go func() {
 		if err := grpcServer.Start(); err != nil {
-			log.Fatalf("gRPC server failed: %v", err)
+			fatal("gRPC server failed", "error", err)
 		}
 	}()
 
 	// Wait for interrupt signal
 	waitForShutdown(grpcServer)
 
-	log.Println("Server shutdown complete")
+	logger.Info("server shutdown complete")
 }
 
-func startHealthServer(healthChecker *health.HealthChecker) {
+// denylistPruneInterval is how often expired access token revocations are
+// deleted. Expired entries are already ignored, so this only bounds storage.
+const denylistPruneInterval = 10 * time.Minute
+
+func pruneAccessTokenDenylist(ctx context.Context, denylist ports.AccessTokenDenylistRepository) {
+	ticker := time.NewTicker(denylistPruneInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			deleted, err := denylist.DeleteExpiredRevocations(ctx)
+			if err != nil {
+				slog.Error("failed to prune access token denylist", "error", err)
+				continue
+			}
+			if deleted > 0 {
+				slog.Debug("pruned access token denylist", "deleted", deleted)
+			}
+		}
+	}
+}
+
+func startHealthServer(healthChecker *health.HealthChecker, jwtProvider *jwt.Provider, promMetrics *metrics.Prometheus) {
 	mux := http.NewServeMux()
 	mux.HandleFunc("/health", healthChecker.HTTPHandler())
 	mux.HandleFunc("/ready", healthChecker.HTTPHandler())
+	mux.HandleFunc("/.well-known/jwks.json", jwtProvider.JWKSHandler())
+	mux.Handle("/metrics", promMetrics.Handler())
 
 	server := &http.Server{
 		Addr:         ":8080",
@@ -84,9 +254,9 @@ func startHealthServer(healthChecker *health.HealthChecker) {
 		IdleTimeout:  120 * time.Second,
 	}
 
-	log.Printf("Starting health server on :8080")
+	slog.Info("starting health server", "addr", server.Addr)
 	if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-		log.Fatalf("Health server failed: %v", err)
+		fatal("health server failed", "error", err)
 	}
 }
 
@@ -96,9 +266,15 @@ func waitForShutdown(grpcServer *grpc.GrpcServer) {
 
 	<-stop
 
-	log.Println("Shutting down server...")
+	slog.Info("shutting down server")
 
 	grpcServer.Stop()
 
-	log.Println("Server stopped gracefully")
+	slog.Info("server stopped gracefully")
+}
+
+// fatal logs an error and exits. Deferred cleanup does not run.
+func fatal(msg string, args ...any) {
+	slog.Error(msg, args...)
+	os.Exit(1)
 }
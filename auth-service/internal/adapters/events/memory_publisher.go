@@ -0,0 +1,62 @@
+package events
+
+import (
+	"context"
+	"sync"
+
+	"github.com/natrayanp/GoMicro/auth-service/internal/domain"
+	"github.com/natrayanp/GoMicro/auth-service/internal/ports"
+)
+
+// MemoryPublisher keeps published events in memory. It is meant for local
+// development and tests, not for delivering events to other services.
+type MemoryPublisher struct {
+	mu     sync.Mutex
+	events []domain.Event
+}
+
+var _ ports.EventPublisherPort = (*MemoryPublisher)(nil)
+
+func NewMemoryPublisher() *MemoryPublisher {
+	return &MemoryPublisher{}
+}
+
+func (p *MemoryPublisher) PublishUserRegistered(ctx context.Context, user *domain.User) error {
+	return p.publish(domain.EventUserRegistered, user)
+}
+
+func (p *MemoryPublisher) PublishUserLoggedIn(ctx context.Context, userID string) error {
+	return p.publish(domain.EventUserLoggedIn, userID)
+}
+
+func (p *MemoryPublisher) PublishUserLoggedOut(ctx context.Context, userID string) error {
+	return p.publish(domain.EventUserLoggedOut, userID)
+}
+
+func (p *MemoryPublisher) PublishPasswordChanged(ctx context.Context, userID string) error {
+	return p.publish(domain.EventPasswordChanged, userID)
+}
+
+func (p *MemoryPublisher) PublishRefreshTokenReused(ctx context.Context, event domain.RefreshTokenReusedEvent) error {
+	return p.publish(domain.EventRefreshTokenReused, event)
+}
+
+func (p *MemoryPublisher) PublishAccountLocked(ctx context.Context, event domain.AccountLockedEvent) error {
+	return p.publish(domain.EventAccountLocked, event)
+}
+
+// Events returns a copy of everything published so far
+func (p *MemoryPublisher) Events() []domain.Event {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return append([]domain.Event(nil), p.events...)
+}
+
+func (p *MemoryPublisher) publish(name string, data any) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.events = append(p.events, domain.Event{Name: name, Data: data})
+	return nil
+}
@@ -0,0 +1,188 @@
+package grpc
+
+import (
+	"context"
+	"strings"
+
+	"github.com/natrayanp/GoMicro/auth-service/internal/logging"
+	"github.com/natrayanp/GoMicro/auth-service/internal/ports"
+	pb "github.com/natrayanp/GoMicro/auth-service/proto/auth/v1"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	reflectionv1 "google.golang.org/grpc/reflection/grpc_reflection_v1"
+	reflectionv1alpha "google.golang.org/grpc/reflection/grpc_reflection_v1alpha"
+)
+
+// ErrorInfo reasons for calls rejected by the auth interceptor
+const (
+	ReasonMissingToken     = "MISSING_TOKEN"
+	ReasonPermissionDenied = "PERMISSION_DENIED"
+)
+
+// AccessLevel says who may call a method
+type AccessLevel int
+
+const (
+	// AccessAdmin allows only configured admin users. It is the zero value
+	// so methods missing from the policy fail closed.
+	AccessAdmin AccessLevel = iota
+	// AccessPublic needs no token
+	AccessPublic
+	// AccessAuthenticated allows any valid access token
+	AccessAuthenticated
+	// AccessSelf allows the user named by the request's user_id, and admins
+	AccessSelf
+)
+
+// MethodPolicy maps full gRPC method names to their access level
+type MethodPolicy map[string]AccessLevel
+
+// DefaultMethodPolicy is the policy for the auth service's own methods.
+// Login and friends are public since they are how callers get a token, and
+// the standard health service is public for load balancer probes.
+func DefaultMethodPolicy() MethodPolicy {
+	return MethodPolicy{
+		pb.AuthService_Register_FullMethodName: AccessPublic,
+		pb.AuthService_Login_FullMethodName:    AccessPublic,
+		pb.AuthService_Refresh_FullMethodName:  AccessPublic,
+		pb.AuthService_Validate_FullMethodName: AccessPublic,
+		pb.AuthService_Logout_FullMethodName:   AccessPublic,
+		pb.AuthService_GetUser_FullMethodName:  AccessSelf,
+
+		healthpb.Health_Check_FullMethodName: AccessPublic,
+		healthpb.Health_List_FullMethodName:  AccessPublic,
+		healthpb.Health_Watch_FullMethodName: AccessPublic,
+	}
+}
+
+// ReflectionMethodPolicy makes server reflection public. NewGrpcServer adds
+// it when reflection is enabled in the config.
+func ReflectionMethodPolicy() MethodPolicy {
+	return MethodPolicy{
+		reflectionv1.ServerReflection_ServerReflectionInfo_FullMethodName:      AccessPublic,
+		reflectionv1alpha.ServerReflection_ServerReflectionInfo_FullMethodName: AccessPublic,
+	}
+}
+
+// userIDRequest matches requests about one user, such as GetUser
+type userIDRequest interface {
+	GetUserId() string
+}
+
+// authInterceptor checks the Bearer access token in the authorization
+// metadata against the method's access level and records the caller with
+// ContextWithPrincipal. Public methods skip the token entirely.
+func authInterceptor(tokens ports.AuthServicePort, policy MethodPolicy, admins map[string]bool) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx, err := authorize(ctx, tokens, policy, admins, info.FullMethod, req)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// streamAuthInterceptor is authInterceptor for streaming RPCs. The request
+// is not known when the stream opens, so AccessSelf streams are admin-only.
+func streamAuthInterceptor(tokens ports.AuthServicePort, policy MethodPolicy, admins map[string]bool) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := authorize(ss.Context(), tokens, policy, admins, info.FullMethod, nil)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &principalStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// authorize applies the policy to one call and returns the context the
+// handler runs with
+func authorize(ctx context.Context, tokens ports.AuthServicePort, policy MethodPolicy, admins map[string]bool, method string, req interface{}) (context.Context, error) {
+	level := policy[method]
+	if level == AccessPublic {
+		return ctx, nil
+	}
+
+	token, ok := bearerToken(ctx)
+	if !ok {
+		return nil, unauthenticatedStatus()
+	}
+
+	claims, err := tokens.ValidateToken(ctx, token)
+	if err != nil {
+		return nil, failed(ctx, "authenticate", err)
+	}
+	userID := claims.Subject
+
+	if !allowed(level, userID, req, admins) {
+		logging.FromContext(ctx).Info("permission denied", "method", method, "user_id", userID)
+		return nil, permissionDeniedStatus()
+	}
+
+	ctx = ContextWithPrincipal(ctx, userID)
+	ctx = logging.NewContext(ctx, logging.FromContext(ctx).With("user_id", userID))
+	return ctx, nil
+}
+
+// principalStream carries the authenticated caller to a stream handler
+type principalStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *principalStream) Context() context.Context {
+	return s.ctx
+}
+
+// allowed applies an access level to an authenticated caller
+func allowed(level AccessLevel, userID string, req interface{}, admins map[string]bool) bool {
+	if admins[userID] {
+		return true
+	}
+
+	switch level {
+	case AccessAuthenticated:
+		return true
+	case AccessSelf:
+		r, ok := req.(userIDRequest)
+		return ok && r.GetUserId() == userID
+	default:
+		return false
+	}
+}
+
+// bearerToken returns the token from an "authorization: Bearer <token>"
+// header
+func bearerToken(ctx context.Context) (string, bool) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return "", false
+	}
+
+	for _, value := range md.Get("authorization") {
+		scheme, token, found := strings.Cut(value, " ")
+		if found && strings.EqualFold(scheme, "bearer") && strings.TrimSpace(token) != "" {
+			return strings.TrimSpace(token), true
+		}
+	}
+	return "", false
+}
+
+func unauthenticatedStatus() error {
+	info := &errdetails.ErrorInfo{
+		Reason: ReasonMissingToken,
+		Domain: ErrorDomain,
+	}
+	return statusWithDetails(codes.Unauthenticated, "missing bearer token", info)
+}
+
+func permissionDeniedStatus() error {
+	info := &errdetails.ErrorInfo{
+		Reason: ReasonPermissionDenied,
+		Domain: ErrorDomain,
+	}
+	return statusWithDetails(codes.PermissionDenied, "permission denied", info)
+}
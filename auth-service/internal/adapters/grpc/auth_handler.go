@@ -2,13 +2,14 @@ package grpc
 
 import (
 	"context"
-	"log"
+	"errors"
+	"log/slog"
 
 	"github.com/natrayanp/GoMicro/auth-service/internal/domain"
+	"github.com/natrayanp/GoMicro/auth-service/internal/logging"
 	"github.com/natrayanp/GoMicro/auth-service/internal/ports"
 	pb "github.com/natrayanp/GoMicro/auth-service/proto/auth/v1"
 
-	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
 
@@ -27,12 +28,11 @@ func NewGrpcAuthHandler(authService ports.AuthServicePort) *GrpcAuthHandler {
 
 // Register handles gRPC Register requests
 func (h *GrpcAuthHandler) Register(ctx context.Context, req *pb.RegisterRequest) (*pb.RegisterResponse, error) {
-	log.Printf("[gRPC] Register request for email: %s", req.Email)
+	logging.FromContext(ctx).Debug("register request", "email", req.Email)
 
 	user, err := h.authService.Register(ctx, req.Email, req.Password)
 	if err != nil {
-		log.Printf("[gRPC] Register failed: %v", err)
-		return nil, mapDomainErrorToGrpc(err)
+		return nil, failed(ctx, "register", err)
 	}
 
 	return &pb.RegisterResponse{
@@ -43,12 +43,16 @@ func (h *GrpcAuthHandler) Register(ctx context.Context, req *pb.RegisterRequest)
 
 // Login handles gRPC Login requests
 func (h *GrpcAuthHandler) Login(ctx context.Context, req *pb.LoginRequest) (*pb.LoginResponse, error) {
-	log.Printf("[gRPC] Login request for email: %s", req.Email)
+	logging.FromContext(ctx).Debug("login request", "email", req.Email)
 
 	tokenPair, err := h.authService.Login(ctx, req.Email, req.Password)
 	if err != nil {
-		log.Printf("[gRPC] Login failed: %v", err)
-		return nil, mapDomainErrorToGrpc(err)
+		var lockedErr *domain.AccountLockedError
+		if errors.As(err, &lockedErr) {
+			setRetryAfter(ctx, lockedErr.RetryAfter())
+		}
+
+		return nil, failed(ctx, "login", err)
 	}
 
 	return &pb.LoginResponse{
@@ -60,12 +64,9 @@ func (h *GrpcAuthHandler) Login(ctx context.Context, req *pb.LoginRequest) (*pb.
 
 // Refresh handles gRPC Refresh requests
 func (h *GrpcAuthHandler) Refresh(ctx context.Context, req *pb.RefreshRequest) (*pb.RefreshResponse, error) {
-	log.Printf("[gRPC] Refresh token request")
-
 	tokenPair, err := h.authService.RefreshToken(ctx, req.RefreshToken)
 	if err != nil {
-		log.Printf("[gRPC] Refresh failed: %v", err)
-		return nil, mapDomainErrorToGrpc(err)
+		return nil, failed(ctx, "refresh", err)
 	}
 
 	return &pb.RefreshResponse{
@@ -77,27 +78,57 @@ func (h *GrpcAuthHandler) Refresh(ctx context.Context, req *pb.RefreshRequest) (
 
 // Validate handles gRPC Validate requests
 func (h *GrpcAuthHandler) Validate(ctx context.Context, req *pb.ValidateRequest) (*pb.ValidateResponse, error) {
-	log.Printf("[gRPC] Validate token request")
-
-	userID, err := h.authService.ValidateToken(ctx, req.Token)
+	claims, err := h.authService.ValidateToken(ctx, req.Token)
 	if err != nil {
-		// For validate endpoint, we return valid=false instead of error
-		return &pb.ValidateResponse{Valid: false}, nil
+		// A rejected token is an answer, not an error; failures to check
+		// the token at all still surface as errors
+		reason, ok := validateFailureReason(err)
+		if !ok {
+			return nil, failed(ctx, "validate", err)
+		}
+		return &pb.ValidateResponse{Valid: false, FailureReason: reason}, nil
 	}
 
 	return &pb.ValidateResponse{
-		Valid:  true,
-		UserId: userID,
+		Valid:     true,
+		UserId:    claims.Subject,
+		ExpiresAt: claims.ExpiresAt.Unix(),
+		IssuedAt:  claims.IssuedAt.Unix(),
+		SessionId: claims.SessionID,
+		Roles:     claims.Roles,
+		Scopes:    claims.Scopes,
 	}, nil
 }
 
-// Logout handles gRPC Logout requests
+// validateFailureReason classifies why a token was rejected, or reports
+// false when the error is not a verdict on the token
+func validateFailureReason(err error) (pb.ValidateFailureReason, bool) {
+	switch {
+	case errors.Is(err, domain.ErrTokenExpired):
+		return pb.ValidateFailureReason_VALIDATE_FAILURE_REASON_EXPIRED, true
+	case errors.Is(err, domain.ErrTokenRevoked):
+		return pb.ValidateFailureReason_VALIDATE_FAILURE_REASON_REVOKED, true
+	case errors.Is(err, domain.ErrWrongTokenType):
+		return pb.ValidateFailureReason_VALIDATE_FAILURE_REASON_WRONG_TYPE, true
+	case errors.Is(err, domain.ErrInvalidToken):
+		return pb.ValidateFailureReason_VALIDATE_FAILURE_REASON_MALFORMED, true
+	default:
+		return pb.ValidateFailureReason_VALIDATE_FAILURE_REASON_UNSPECIFIED, false
+	}
+}
+
+// Logout handles gRPC Logout requests. Both tokens are revoked even if
+// one of them fails, so a stale refresh token cannot keep a live access
+// token in use.
 func (h *GrpcAuthHandler) Logout(ctx context.Context, req *pb.LogoutRequest) (*pb.LogoutResponse, error) {
-	log.Printf("[gRPC] Logout request")
+	var accessErr error
+	if req.AccessToken != "" {
+		accessErr = h.authService.RevokeAccessToken(ctx, req.AccessToken)
+	}
+	refreshErr := h.authService.RevokeToken(ctx, req.RefreshToken)
 
-	if err := h.authService.RevokeToken(ctx, req.RefreshToken); err != nil {
-		log.Printf("[gRPC] Logout failed: %v", err)
-		return nil, mapDomainErrorToGrpc(err)
+	if err := errors.Join(accessErr, refreshErr); err != nil {
+		return nil, failed(ctx, "logout", err)
 	}
 
 	return &pb.LogoutResponse{Success: true}, nil
@@ -105,12 +136,11 @@ func (h *GrpcAuthHandler) Logout(ctx context.Context, req *pb.LogoutRequest) (*p
 
 // GetUser handles gRPC GetUser requests
 func (h *GrpcAuthHandler) GetUser(ctx context.Context, req *pb.GetUserRequest) (*pb.GetUserResponse, error) {
-	log.Printf("[gRPC] GetUser request for ID: %s", req.UserId)
+	logging.FromContext(ctx).Debug("get user request", "user_id", req.UserId)
 
 	user, err := h.authService.GetUserByID(ctx, req.UserId)
 	if err != nil {
-		log.Printf("[gRPC] GetUser failed: %v", err)
-		return nil, mapDomainErrorToGrpc(err)
+		return nil, failed(ctx, "get user", err)
 	}
 
 	return &pb.GetUserResponse{
@@ -123,22 +153,16 @@ func (h *GrpcAuthHandler) GetUser(ctx context.Context, req *pb.GetUserRequest) (
 	}, nil
 }
 
-// mapDomainErrorToGrpc maps domain errors to gRPC status errors
-func mapDomainErrorToGrpc(err error) error {
-	switch err {
-	case domain.ErrInvalidCredentials:
-		return status.Error(codes.Unauthenticated, "invalid credentials")
-	case domain.ErrUserExists:
-		return status.Error(codes.AlreadyExists, "user already exists")
-	case domain.ErrUserNotFound:
-		return status.Error(codes.NotFound, "user not found")
-	case domain.ErrInvalidToken, domain.ErrTokenExpired, domain.ErrTokenRevoked:
-		return status.Error(codes.Unauthenticated, "invalid token")
-	case domain.ErrInvalidEmail:
-		return status.Error(codes.InvalidArgument, "invalid email")
-	case domain.ErrPasswordTooShort:
-		return status.Error(codes.InvalidArgument, "password too short")
-	default:
-		return status.Error(codes.Internal, "internal server error")
+// failed logs a failed call and returns its gRPC status. Caller mistakes
+// are logged at info, server faults at error with the underlying cause.
+func failed(ctx context.Context, operation string, err error) error {
+	st := mapDomainErrorToGrpc(err)
+
+	level := slog.LevelInfo
+	if isServerError(status.Code(st)) {
+		level = slog.LevelError
 	}
+	logging.FromContext(ctx).Log(ctx, level, operation+" failed", "error", err)
+
+	return st
 }
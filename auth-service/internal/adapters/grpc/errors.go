@@ -0,0 +1,160 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/natrayanp/GoMicro/auth-service/internal/domain"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// ErrorDomain is the ErrorInfo domain of every reason this service returns
+const ErrorDomain = "auth-service"
+
+// ReasonRateLimited is the ErrorInfo reason of a call rejected by the rate
+// limiter; the others come from the domain errors
+const ReasonRateLimited = "RATE_LIMITED"
+
+// grpcErrors maps domain errors to a status code and a client-facing
+// message. Entries are matched in order with errors.Is, so wrapped errors
+// map too. Token failures share one message on purpose; the ErrorInfo
+// reason tells them apart.
+var grpcErrors = []struct {
+	err     error
+	code    codes.Code
+	message string
+}{
+	{domain.ErrInvalidCredentials, codes.Unauthenticated, "invalid credentials"},
+	{domain.ErrUserExists, codes.AlreadyExists, "user already exists"},
+	{domain.ErrUserNotFound, codes.NotFound, "user not found"},
+	{domain.ErrInvalidToken, codes.Unauthenticated, "invalid token"},
+	{domain.ErrTokenExpired, codes.Unauthenticated, "invalid token"},
+	{domain.ErrTokenRevoked, codes.Unauthenticated, "invalid token"},
+	{domain.ErrWrongTokenType, codes.Unauthenticated, "invalid token"},
+	{domain.ErrRefreshTokenReused, codes.Unauthenticated, "refresh token reuse detected"},
+	{domain.ErrInvalidEmail, codes.InvalidArgument, "invalid email"},
+	{domain.ErrPasswordPolicy, codes.InvalidArgument, "password does not meet policy"},
+	{domain.ErrPasswordTooShort, codes.InvalidArgument, "password too short"},
+	{domain.ErrAccountLocked, codes.ResourceExhausted, "account temporarily locked"},
+}
+
+// mapDomainErrorToGrpc maps domain errors to gRPC status errors. Each
+// status carries an ErrorInfo with the domain reason and metadata, plus
+// BadRequest field violations for invalid input and RetryInfo for a locked
+// account. Anything that is not a domain error is Internal.
+func mapDomainErrorToGrpc(err error) error {
+	var domainErr *domain.Error
+	if !errors.As(err, &domainErr) {
+		return status.Error(codes.Internal, "internal server error")
+	}
+
+	code, message := codes.Internal, "internal server error"
+	for _, m := range grpcErrors {
+		if errors.Is(err, m.err) {
+			code, message = m.code, m.message
+			break
+		}
+	}
+
+	info := &errdetails.ErrorInfo{
+		Reason:   domainErr.Reason,
+		Domain:   ErrorDomain,
+		Metadata: domainErr.Metadata,
+	}
+	details := []protoadapt.MessageV1{info}
+
+	var policyErr *domain.PasswordPolicyError
+	var lockedErr *domain.AccountLockedError
+	switch {
+	case errors.As(err, &policyErr):
+		details = append(details, passwordViolations(policyErr))
+	case errors.Is(err, domain.ErrPasswordTooShort):
+		details = append(details, fieldViolation("password", domain.ErrPasswordTooShort))
+	case errors.Is(err, domain.ErrInvalidEmail):
+		details = append(details, fieldViolation("email", domain.ErrInvalidEmail))
+	case errors.As(err, &lockedErr):
+		info.Metadata = withMetadata(info.Metadata, "locked_until", lockedErr.Until.UTC().Format(time.RFC3339))
+		details = append(details, retryInfo(lockedErr.RetryAfter()))
+	}
+
+	return statusWithDetails(code, message, details...)
+}
+
+// passwordViolations reports every failed password rule as a BadRequest
+// field violation so clients can show all of them at once
+func passwordViolations(policyErr *domain.PasswordPolicyError) *errdetails.BadRequest {
+	badRequest := &errdetails.BadRequest{}
+	for _, v := range policyErr.Violations {
+		badRequest.FieldViolations = append(badRequest.FieldViolations, &errdetails.BadRequest_FieldViolation{
+			Field:       "password",
+			Description: v.Message,
+			Reason:      "PASSWORD_" + strings.ToUpper(string(v.Rule)),
+		})
+	}
+	return badRequest
+}
+
+// fieldViolation reports a single invalid request field
+func fieldViolation(field string, err *domain.Error) *errdetails.BadRequest {
+	return &errdetails.BadRequest{
+		FieldViolations: []*errdetails.BadRequest_FieldViolation{{
+			Field:       field,
+			Description: err.Message,
+			Reason:      err.Reason,
+		}},
+	}
+}
+
+// rateLimitedStatus builds a ResourceExhausted status whose RetryInfo
+// detail says when to try again
+func rateLimitedStatus(retryAfter time.Duration) error {
+	info := &errdetails.ErrorInfo{
+		Reason: ReasonRateLimited,
+		Domain: ErrorDomain,
+	}
+	return statusWithDetails(codes.ResourceExhausted, "rate limit exceeded", info, retryInfo(retryAfter))
+}
+
+func retryInfo(retryAfter time.Duration) *errdetails.RetryInfo {
+	return &errdetails.RetryInfo{
+		RetryDelay: durationpb.New(retryAfter),
+	}
+}
+
+// statusWithDetails falls back to a bare status if the details cannot be
+// attached, which only happens when they fail to marshal
+func statusWithDetails(code codes.Code, message string, details ...protoadapt.MessageV1) error {
+	st, err := status.New(code, message).WithDetails(details...)
+	if err != nil {
+		return status.Error(code, message)
+	}
+	return st.Err()
+}
+
+// withMetadata adds a key to a copy of metadata, leaving shared maps alone
+func withMetadata(metadata map[string]string, key, value string) map[string]string {
+	merged := make(map[string]string, len(metadata)+1)
+	for k, v := range metadata {
+		merged[k] = v
+	}
+	merged[key] = value
+	return merged
+}
+
+// setRetryAfter adds a retry-after trailer in whole seconds, a plain
+// HTTP-style hint for clients that ignore status details
+func setRetryAfter(ctx context.Context, retryAfter time.Duration) {
+	seconds := int(math.Ceil(retryAfter.Seconds()))
+	_ = grpc.SetTrailer(ctx, metadata.Pairs("retry-after", strconv.Itoa(seconds)))
+}
@@ -0,0 +1,73 @@
+package grpc
+
+import (
+	"context"
+	"log/slog"
+	"time"
+	"unicode"
+
+	"github.com/natrayanp/GoMicro/auth-service/internal/logging"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// requestIDHeader carries the request ID in both directions
+const requestIDHeader = "x-request-id"
+
+// loggingInterceptor gives every request an ID and a logger carrying it,
+// and logs one line per call with its status code and latency
+func loggingInterceptor(logger *slog.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		start := time.Now()
+
+		requestID := incomingRequestID(ctx)
+		_ = grpc.SetHeader(ctx, metadata.Pairs(requestIDHeader, requestID))
+
+		reqLogger := logger.With(slog.String("request_id", requestID))
+		if span := trace.SpanContextFromContext(ctx); span.IsValid() {
+			reqLogger = reqLogger.With(slog.String("trace_id", span.TraceID().String()))
+		}
+		ctx = logging.NewContext(ctx, reqLogger)
+
+		resp, err := handler(ctx, req)
+
+		code := status.Code(err)
+		level := slog.LevelInfo
+		if isServerError(code) {
+			level = slog.LevelError
+		}
+		reqLogger.LogAttrs(ctx, level, "grpc request",
+			slog.String("method", info.FullMethod),
+			slog.String("code", code.String()),
+			slog.Float64("duration_ms", float64(time.Since(start).Microseconds())/1000),
+		)
+
+		return resp, err
+	}
+}
+
+// incomingRequestID reuses the caller's request ID when it looks sane, so
+// one ID can follow a request across services, and makes one up otherwise
+func incomingRequestID(ctx context.Context) string {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if values := md.Get(requestIDHeader); len(values) > 0 && validRequestID(values[0]) {
+		return values[0]
+	}
+	return uuid.NewString()
+}
+
+func validRequestID(id string) bool {
+	if id == "" || len(id) > 128 {
+		return false
+	}
+	for _, r := range id {
+		if r > unicode.MaxASCII || !unicode.IsPrint(r) {
+			return false
+		}
+	}
+	return true
+}
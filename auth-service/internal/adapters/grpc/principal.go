@@ -0,0 +1,16 @@
+package grpc
+
+import "context"
+
+type principalKey struct{}
+
+// ContextWithPrincipal records the authenticated user ID for the request
+func ContextWithPrincipal(ctx context.Context, userID string) context.Context {
+	return context.WithValue(ctx, principalKey{}, userID)
+}
+
+// PrincipalFromContext returns the authenticated user ID, if any
+func PrincipalFromContext(ctx context.Context) (string, bool) {
+	userID, ok := ctx.Value(principalKey{}).(string)
+	return userID, ok && userID != ""
+}
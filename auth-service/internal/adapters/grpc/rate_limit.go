@@ -0,0 +1,61 @@
+package grpc
+
+import (
+	"context"
+	"net"
+	"path"
+
+	"github.com/natrayanp/GoMicro/auth-service/internal/logging"
+	"github.com/natrayanp/GoMicro/auth-service/internal/ratelimit"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/peer"
+)
+
+// emailRequest matches requests that carry an email, such as Login and
+// Register
+type emailRequest interface {
+	GetEmail() string
+}
+
+// rateLimitInterceptor rejects requests over their method's limits with
+// ResourceExhausted. A failing store lets requests through rather than
+// taking the service down with it.
+func rateLimitInterceptor(limiter *ratelimit.Limiter) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		method := path.Base(info.FullMethod)
+
+		decision, err := limiter.Check(ctx, method, requestKeys(ctx, req))
+		if err != nil {
+			logging.FromContext(ctx).Warn("rate limit check failed", "method", info.FullMethod, "error", err)
+			return handler(ctx, req)
+		}
+
+		if !decision.Allowed {
+			setRetryAfter(ctx, decision.RetryAfter)
+			return nil, rateLimitedStatus(decision.RetryAfter)
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+// requestKeys collects everything a rate limit rule can be keyed by
+func requestKeys(ctx context.Context, req interface{}) ratelimit.Keys {
+	var keys ratelimit.Keys
+
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		keys.IP = p.Addr.String()
+		if host, _, err := net.SplitHostPort(keys.IP); err == nil {
+			keys.IP = host
+		}
+	}
+
+	if r, ok := req.(emailRequest); ok {
+		keys.Email = r.GetEmail()
+	}
+
+	keys.Principal, _ = PrincipalFromContext(ctx)
+
+	return keys
+}
@@ -0,0 +1,53 @@
+package grpc
+
+import (
+	"context"
+	"runtime/debug"
+
+	"github.com/natrayanp/GoMicro/auth-service/internal/logging"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// recoveryInterceptor turns a panicking handler into an Internal error
+// instead of crashing the server
+func recoveryInterceptor(metrics RPCObserver) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = recovered(ctx, info.FullMethod, r, metrics)
+			}
+		}()
+		return handler(ctx, req)
+	}
+}
+
+// streamRecoveryInterceptor is recoveryInterceptor for streaming RPCs
+func streamRecoveryInterceptor(metrics RPCObserver) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = recovered(ss.Context(), info.FullMethod, r, metrics)
+			}
+		}()
+		return handler(srv, ss)
+	}
+}
+
+// recovered logs a panic with its stack trace and returns a generic status,
+// so panic values never reach clients
+func recovered(ctx context.Context, method string, r interface{}, metrics RPCObserver) error {
+	logging.FromContext(ctx).Error("panic in gRPC method",
+		"method", method,
+		"panic", r,
+		"stack", string(debug.Stack()),
+	)
+
+	if metrics != nil {
+		metrics.ObservePanic(method)
+	}
+
+	return status.Error(codes.Internal, "internal server error")
+}
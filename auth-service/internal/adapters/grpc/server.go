@@ -3,15 +3,18 @@ package grpc
 import (
 	"context"
 	"fmt"
-	"log"
+	"log/slog"
 	"net"
 	"strconv"
+	"time"
 
 	"github.com/natrayanp/GoMicro/auth-service/internal/config"
+	"github.com/natrayanp/GoMicro/auth-service/internal/ratelimit"
 	pb "github.com/natrayanp/GoMicro/auth-service/proto/auth/v1"
 
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
 )
 
 // GrpcServer manages the gRPC server lifecycle
@@ -19,23 +22,113 @@ type GrpcServer struct {
 	config     *config.Config
 	grpcServer *grpc.Server
 	handler    *GrpcAuthHandler
+	logger     *slog.Logger
 }
 
-// NewGrpcServer creates a new gRPC server
-func NewGrpcServer(cfg *config.Config, handler *GrpcAuthHandler) *GrpcServer {
+// ServerOption configures optional GrpcServer interceptors
+type ServerOption func(*serverOptions)
+
+type serverOptions struct {
+	logger  *slog.Logger
+	limiter *ratelimit.Limiter
+	metrics RPCObserver
+	policy  MethodPolicy
+}
+
+// RPCObserver records finished requests and recovered panics, for example
+// metrics.Prometheus
+type RPCObserver interface {
+	ObserveRPC(method, code string, duration time.Duration)
+	ObservePanic(method string)
+}
+
+// WithLogger sets the logger for request logs. The default is slog.Default.
+func WithLogger(logger *slog.Logger) ServerOption {
+	return func(o *serverOptions) {
+		o.logger = logger
+	}
+}
+
+// WithRateLimiter rejects requests over the limiter's rules
+func WithRateLimiter(limiter *ratelimit.Limiter) ServerOption {
+	return func(o *serverOptions) {
+		o.limiter = limiter
+	}
+}
+
+// WithMetrics reports the count and latency of every request
+func WithMetrics(metrics RPCObserver) ServerOption {
+	return func(o *serverOptions) {
+		o.metrics = metrics
+	}
+}
+
+// WithMethodPolicy adds or overrides access levels on top of
+// DefaultMethodPolicy, for example for services registered next to auth
+func WithMethodPolicy(policy MethodPolicy) ServerOption {
+	return func(o *serverOptions) {
+		for method, level := range policy {
+			o.policy[method] = level
+		}
+	}
+}
+
+// NewGrpcServer creates a new gRPC server. Every call, unary or streaming,
+// passes the auth interceptor, which validates tokens with the handler's
+// service and lets the users in cfg.Auth.AdminUserIDs call anything. Server
+// reflection is registered, and public, only with cfg.Server.Reflection.
+func NewGrpcServer(cfg *config.Config, handler *GrpcAuthHandler, opts ...ServerOption) *GrpcServer {
+	o := serverOptions{logger: slog.Default(), policy: DefaultMethodPolicy()}
+	if cfg.Server.Reflection {
+		for method, level := range ReflectionMethodPolicy() {
+			o.policy[method] = level
+		}
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	interceptors := []grpc.UnaryServerInterceptor{
+		tracingInterceptor(),
+		loggingInterceptor(o.logger),
+	}
+	// Measure outermost so rejected and panicking requests are counted too
+	if o.metrics != nil {
+		interceptors = append(interceptors, metricsInterceptor(o.metrics))
+	}
+	interceptors = append(interceptors, recoveryInterceptor(o.metrics))
+
+	// Authenticate before rate limiting so principal keys are known
+	admins := make(map[string]bool, len(cfg.Auth.AdminUserIDs))
+	for _, id := range cfg.Auth.AdminUserIDs {
+		admins[id] = true
+	}
+	interceptors = append(interceptors, authInterceptor(handler.authService, o.policy, admins))
+	streamInterceptors := []grpc.StreamServerInterceptor{
+		streamRecoveryInterceptor(o.metrics),
+		streamAuthInterceptor(handler.authService, o.policy, admins),
+	}
+
+	if o.limiter != nil {
+		interceptors = append(interceptors, rateLimitInterceptor(o.limiter))
+	}
+
 	// Create gRPC server with interceptors
 	server := grpc.NewServer(
-		grpc.ChainUnaryInterceptor(
-			loggingInterceptor(),
-			recoveryInterceptor(),
-			metricsInterceptor(),
-		),
+		grpc.ChainUnaryInterceptor(interceptors...),
+		grpc.ChainStreamInterceptor(streamInterceptors...),
 	)
 
+	// Reflection lets tools like grpcurl list and call methods
+	if cfg.Server.Reflection {
+		reflection.Register(server)
+	}
+
 	return &GrpcServer{
 		config:     cfg,
 		grpcServer: server,
 		handler:    handler,
+		logger:     o.logger,
 	}
 }
 
@@ -53,10 +146,7 @@ func (s *GrpcServer) Start() error {
 		return fmt.Errorf("failed to listen on %s: %w", addr, err)
 	}
 
-	// Enable reflection for debugging and tools like grpcurl
-	reflection.Register(s.grpcServer)
-
-	log.Printf("🚀 gRPC server listening on %s", addr)
+	s.logger.Info("gRPC server listening", "addr", addr)
 
 	return s.grpcServer.Serve(lis)
 }
@@ -65,7 +155,7 @@ func (s *GrpcServer) Start() error {
 func (s *GrpcServer) Stop() {
 	if s.grpcServer != nil {
 		s.grpcServer.GracefulStop()
-		log.Println("gRPC server stopped gracefully")
+		s.logger.Info("gRPC server stopped gracefully")
 	}
 }
 
@@ -75,28 +165,11 @@ func (s *GrpcServer) GrpcServer() *grpc.Server {
 }
 
 // Interceptors
-func loggingInterceptor() grpc.UnaryServerInterceptor {
-	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
-		log.Printf("gRPC method called: %s", info.FullMethod)
-		return handler(ctx, req)
-	}
-}
-
-func recoveryInterceptor() grpc.UnaryServerInterceptor {
-	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
-		defer func() {
-			if r := recover(); r != nil {
-				err = fmt.Errorf("panic recovered: %v", r)
-				log.Printf("Panic in gRPC method %s: %v", info.FullMethod, r)
-			}
-		}()
-		return handler(ctx, req)
-	}
-}
-
-func metricsInterceptor() grpc.UnaryServerInterceptor {
+func metricsInterceptor(metrics RPCObserver) grpc.UnaryServerInterceptor {
 	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
-		// TODO: Add metrics collection
-		return handler(ctx, req)
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		metrics.ObserveRPC(info.FullMethod, status.Code(err).String(), time.Since(start))
+		return resp, err
 	}
 }
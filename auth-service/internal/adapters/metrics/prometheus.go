@@ -0,0 +1,161 @@
+// Package metrics exposes service metrics in the Prometheus format.
+package metrics
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/natrayanp/GoMicro/auth-service/internal/ports"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "auth"
+
+// Prometheus records gRPC and domain metrics in its own registry
+type Prometheus struct {
+	registry *prometheus.Registry
+
+	grpcRequests *prometheus.CounterVec
+	grpcDuration *prometheus.HistogramVec
+	grpcPanics   *prometheus.CounterVec
+
+	registrations  prometheus.Counter
+	logins         *prometheus.CounterVec
+	accountsLocked prometheus.Counter
+	refreshReuse   prometheus.Counter
+	revocations    *prometheus.CounterVec
+	cacheLookups   *prometheus.CounterVec
+}
+
+var _ ports.MetricsRecorder = (*Prometheus)(nil)
+
+// NewPrometheus creates the metrics, including Go runtime and process
+// metrics, in a fresh registry
+func NewPrometheus() *Prometheus {
+	m := &Prometheus{
+		registry: prometheus.NewRegistry(),
+
+		grpcRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "grpc_requests_total",
+			Help:      "gRPC requests handled, by method and status code.",
+		}, []string{"method", "code"}),
+		grpcDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "grpc_request_duration_seconds",
+			Help:      "gRPC request latency, by method and status code.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "code"}),
+		grpcPanics: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "grpc_panics_total",
+			Help:      "Panics recovered in gRPC handlers, by method.",
+		}, []string{"method"}),
+
+		registrations: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "registrations_total",
+			Help:      "Users registered.",
+		}),
+		logins: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "logins_total",
+			Help:      "Login attempts, by result.",
+		}, []string{"result"}),
+		accountsLocked: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "accounts_locked_total",
+			Help:      "Accounts locked after repeated failed logins.",
+		}),
+		refreshReuse: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "refresh_token_reuse_total",
+			Help:      "Rotated refresh tokens presented again.",
+		}),
+		revocations: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "token_revocations_total",
+			Help:      "Refresh token revocations, by reason.",
+		}, []string{"reason"}),
+		cacheLookups: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "validation_cache_lookups_total",
+			Help:      "Access token validation cache lookups, by result (hit or miss).",
+		}, []string{"result"}),
+	}
+
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.grpcRequests,
+		m.grpcDuration,
+		m.grpcPanics,
+		m.registrations,
+		m.logins,
+		m.accountsLocked,
+		m.refreshReuse,
+		m.revocations,
+		m.cacheLookups,
+	)
+
+	return m
+}
+
+// RegisterPool adds connection pool statistics
+func (m *Prometheus) RegisterPool(pool *pgxpool.Pool) {
+	m.registry.MustRegister(newPoolCollector(pool))
+}
+
+// Handler serves the registry for scraping
+func (m *Prometheus) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{Registry: m.registry})
+}
+
+// ObserveRPC records one finished gRPC request
+func (m *Prometheus) ObserveRPC(method, code string, duration time.Duration) {
+	m.grpcRequests.WithLabelValues(method, code).Inc()
+	m.grpcDuration.WithLabelValues(method, code).Observe(duration.Seconds())
+}
+
+// ObservePanic records one recovered handler panic
+func (m *Prometheus) ObservePanic(method string) {
+	m.grpcPanics.WithLabelValues(method).Inc()
+}
+
+// UserRegistered implements MetricsRecorder.UserRegistered
+func (m *Prometheus) UserRegistered() {
+	m.registrations.Inc()
+}
+
+// LoginAttempted implements MetricsRecorder.LoginAttempted
+func (m *Prometheus) LoginAttempted(result string) {
+	m.logins.WithLabelValues(result).Inc()
+}
+
+// AccountLocked implements MetricsRecorder.AccountLocked
+func (m *Prometheus) AccountLocked() {
+	m.accountsLocked.Inc()
+}
+
+// RefreshTokenReused implements MetricsRecorder.RefreshTokenReused
+func (m *Prometheus) RefreshTokenReused() {
+	m.refreshReuse.Inc()
+}
+
+// TokensRevoked implements MetricsRecorder.TokensRevoked
+func (m *Prometheus) TokensRevoked(reason string) {
+	m.revocations.WithLabelValues(reason).Inc()
+}
+
+// ValidationCacheLookup implements MetricsRecorder.ValidationCacheLookup
+func (m *Prometheus) ValidationCacheLookup(hit bool) {
+	result := "miss"
+	if hit {
+		result = "hit"
+	}
+	m.cacheLookups.WithLabelValues(result).Inc()
+}
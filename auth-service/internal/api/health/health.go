@@ -18,6 +18,11 @@ func NewHealthChecker(db *pgxpool.Pool) *HealthChecker {
 }
 
 func (h *HealthChecker) Check(ctx context.Context) error {
+	// In-memory storage has no database to check
+	if h.db == nil {
+		return nil
+	}
+
 	// Check database connection
 	if err := h.db.Ping(ctx); err != nil {
 		return fmt.Errorf("database connection failed: %w", err)
@@ -0,0 +1,55 @@
+package jwt
+
+import (
+	"strings"
+	"time"
+
+	"github.com/natrayanp/GoMicro/auth-service/internal/domain"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// tokenClaims is the wire format shared by access and refresh tokens. Roles
+// and scope are only set on access tokens; scope is space separated as in
+// RFC 9068. iat_us repeats iat in microseconds, so revocation watermarks
+// can tell tokens issued just before a revocation from those issued just
+// after it.
+type tokenClaims struct {
+	Type           domain.TokenType `json:"typ"`
+	SessionID      string           `json:"sid,omitempty"`
+	Roles          []string         `json:"roles,omitempty"`
+	Scope          string           `json:"scope,omitempty"`
+	IssuedAtMicros int64            `json:"iat_us,omitempty"`
+	jwt.RegisteredClaims
+}
+
+func (c *tokenClaims) toDomain() *domain.TokenClaims {
+	return &domain.TokenClaims{
+		ID:        c.ID,
+		Subject:   c.Subject,
+		Type:      c.Type,
+		SessionID: c.SessionID,
+		Roles:     c.Roles,
+		Scopes:    strings.Fields(c.Scope),
+		Issuer:    c.Issuer,
+		Audience:  c.Audience,
+		IssuedAt:  c.issuedAt(),
+		NotBefore: timeOf(c.NotBefore),
+		ExpiresAt: timeOf(c.ExpiresAt),
+	}
+}
+
+// issuedAt prefers iat_us, falling back to iat for tokens issued without it
+func (c *tokenClaims) issuedAt() time.Time {
+	if c.IssuedAtMicros != 0 {
+		return time.UnixMicro(c.IssuedAtMicros)
+	}
+	return timeOf(c.IssuedAt)
+}
+
+func timeOf(t *jwt.NumericDate) time.Time {
+	if t == nil {
+		return time.Time{}
+	}
+	return t.Time
+}
@@ -0,0 +1,56 @@
+package jwt
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+)
+
+// JWK is a public JSON Web Key (RFC 7517)
+type JWK struct {
+	KeyType   string `json:"kty"`
+	KeyID     string `json:"kid"`
+	Use       string `json:"use"`
+	Algorithm string `json:"alg"`
+	N         string `json:"n,omitempty"`
+	E         string `json:"e,omitempty"`
+	Curve     string `json:"crv,omitempty"`
+	X         string `json:"x,omitempty"`
+	Y         string `json:"y,omitempty"`
+}
+
+// JWKS is a JSON Web Key Set document
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// JWKS returns the public verification keys. Shared HMAC secrets are
+// never included, so the set is empty when signing with HS256.
+func (p *Provider) JWKS() JWKS {
+	set := JWKS{Keys: []JWK{}}
+
+	for _, key := range p.verificationKeys() {
+		if key.IsSymmetric() {
+			continue
+		}
+
+		jwk, err := key.JWK()
+		if err != nil {
+			slog.Warn("skipping key in JWKS", "kid", key.ID, "error", err)
+			continue
+		}
+		set.Keys = append(set.Keys, jwk)
+	}
+
+	return set
+}
+
+// JWKSHandler serves the JWKS document for downstream verifiers
+func (p *Provider) JWKSHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "public, max-age=300")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(p.JWKS())
+	}
+}
@@ -0,0 +1,121 @@
+package jwt
+
+import (
+	"sync"
+	"time"
+)
+
+// Keyring holds the active signing key plus retired keys that are still
+// accepted for verification until their grace period runs out
+type Keyring struct {
+	mu      sync.RWMutex
+	active  *SigningKey
+	retired []retiredKey
+	grace   time.Duration
+}
+
+type retiredKey struct {
+	key       *SigningKey
+	expiresAt time.Time
+}
+
+// NewKeyring creates a keyring with a single active key
+func NewKeyring(active *SigningKey, grace time.Duration) *Keyring {
+	return &Keyring{
+		active: active,
+		grace:  grace,
+	}
+}
+
+// Active returns the key new tokens are signed with
+func (k *Keyring) Active() *SigningKey {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	return k.active
+}
+
+// Lookup finds a verification key by kid
+func (k *Keyring) Lookup(kid string) (*SigningKey, bool) {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	if k.active.ID == kid {
+		return k.active, true
+	}
+
+	now := time.Now()
+	for _, r := range k.retired {
+		if r.key.ID == kid && now.Before(r.expiresAt) {
+			return r.key, true
+		}
+	}
+
+	return nil, false
+}
+
+// Promote makes key the active signing key. The previous active key is
+// retired and keeps verifying tokens for the grace period.
+func (k *Keyring) Promote(key *SigningKey) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	if k.active.ID == key.ID {
+		return
+	}
+
+	k.retireLocked(k.active)
+	k.active = key
+
+	// A key promoted back from retirement must not linger in both lists
+	kept := k.retired[:0]
+	for _, r := range k.retired {
+		if r.key.ID != key.ID {
+			kept = append(kept, r)
+		}
+	}
+	k.retired = kept
+}
+
+// Retire adds a verification-only key, e.g. a previous secret loaded at
+// startup, which is accepted for the grace period from now
+func (k *Keyring) Retire(key *SigningKey) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	if k.active.ID == key.ID {
+		return
+	}
+
+	k.retireLocked(key)
+}
+
+// VerificationKeys returns the active key followed by unexpired retired keys
+func (k *Keyring) VerificationKeys() []*SigningKey {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	now := time.Now()
+	keys := []*SigningKey{k.active}
+	for _, r := range k.retired {
+		if now.Before(r.expiresAt) {
+			keys = append(keys, r.key)
+		}
+	}
+
+	return keys
+}
+
+func (k *Keyring) retireLocked(key *SigningKey) {
+	now := time.Now()
+
+	// Drop expired entries and any older copy of the same key
+	kept := k.retired[:0]
+	for _, r := range k.retired {
+		if now.Before(r.expiresAt) && r.key.ID != key.ID {
+			kept = append(kept, r)
+		}
+	}
+
+	k.retired = append(kept, retiredKey{key: key, expiresAt: now.Add(k.grace)})
+}
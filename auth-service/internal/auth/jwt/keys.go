@@ -0,0 +1,196 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// SigningKey is a key the provider can sign and verify tokens with.
+// For HMAC keys Private and Public are the same shared secret.
+type SigningKey struct {
+	ID      string
+	Method  jwt.SigningMethod
+	Private crypto.PrivateKey
+	Public  crypto.PublicKey
+}
+
+// NewHMACKey creates an HS256 key from a shared secret
+func NewHMACKey(secret, kid string) *SigningKey {
+	if kid == "" {
+		sum := sha256.Sum256([]byte(secret))
+		kid = "hs256-" + hex.EncodeToString(sum[:8])
+	}
+
+	return &SigningKey{
+		ID:      kid,
+		Method:  jwt.SigningMethodHS256,
+		Private: []byte(secret),
+		Public:  []byte(secret),
+	}
+}
+
+// LoadSigningKey reads a PEM encoded private key for the given method
+// (RS256, ES256 or EdDSA). When kid is empty it is derived from the
+// RFC 7638 thumbprint of the public key.
+func LoadSigningKey(method, path, kid string) (*SigningKey, error) {
+	pemBytes, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read private key %s: %w", path, err)
+	}
+
+	return ParseSigningKey(method, pemBytes, kid)
+}
+
+// ParseSigningKey parses a PEM encoded private key for the given method
+func ParseSigningKey(method string, pemBytes []byte, kid string) (*SigningKey, error) {
+	key := &SigningKey{ID: kid}
+
+	switch method {
+	case jwt.SigningMethodRS256.Alg():
+		priv, err := jwt.ParseRSAPrivateKeyFromPEM(pemBytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse RSA private key: %w", err)
+		}
+		key.Method, key.Private, key.Public = jwt.SigningMethodRS256, priv, &priv.PublicKey
+
+	case jwt.SigningMethodES256.Alg():
+		priv, err := jwt.ParseECPrivateKeyFromPEM(pemBytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse ECDSA private key: %w", err)
+		}
+		if priv.Curve != elliptic.P256() {
+			return nil, fmt.Errorf("ES256 requires a P-256 key, got %s", priv.Curve.Params().Name)
+		}
+		key.Method, key.Private, key.Public = jwt.SigningMethodES256, priv, &priv.PublicKey
+
+	case jwt.SigningMethodEdDSA.Alg():
+		priv, err := jwt.ParseEdPrivateKeyFromPEM(pemBytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse Ed25519 private key: %w", err)
+		}
+		edPriv, ok := priv.(ed25519.PrivateKey)
+		if !ok {
+			return nil, fmt.Errorf("EdDSA requires an Ed25519 key")
+		}
+		key.Method, key.Private, key.Public = jwt.SigningMethodEdDSA, edPriv, edPriv.Public()
+
+	default:
+		return nil, fmt.Errorf("unsupported signing method %q", method)
+	}
+
+	if key.ID == "" {
+		thumbprint, err := key.Thumbprint()
+		if err != nil {
+			return nil, err
+		}
+		key.ID = thumbprint
+	}
+
+	return key, nil
+}
+
+// IsSymmetric reports whether the key is a shared secret that must never
+// be published
+func (k *SigningKey) IsSymmetric() bool {
+	_, ok := k.Public.([]byte)
+	return ok
+}
+
+// JWK returns the public half of the key as a JSON Web Key
+func (k *SigningKey) JWK() (JWK, error) {
+	jwk := JWK{
+		KeyID:     k.ID,
+		Use:       "sig",
+		Algorithm: k.Method.Alg(),
+	}
+
+	switch pub := k.Public.(type) {
+	case *rsa.PublicKey:
+		jwk.KeyType = "RSA"
+		jwk.N = encodeSegment(pub.N.Bytes())
+		jwk.E = encodeSegment(bigEndian(pub.E))
+	case *ecdsa.PublicKey:
+		ecdh, err := pub.ECDH()
+		if err != nil {
+			return JWK{}, fmt.Errorf("invalid ECDSA public key: %w", err)
+		}
+		// Uncompressed point: 0x04 || X || Y
+		point := ecdh.Bytes()[1:]
+		size := len(point) / 2
+		jwk.KeyType = "EC"
+		jwk.Curve = pub.Curve.Params().Name
+		jwk.X = encodeSegment(point[:size])
+		jwk.Y = encodeSegment(point[size:])
+	case ed25519.PublicKey:
+		jwk.KeyType = "OKP"
+		jwk.Curve = "Ed25519"
+		jwk.X = encodeSegment(pub)
+	default:
+		return JWK{}, fmt.Errorf("key %s has no public JWK representation", k.ID)
+	}
+
+	return jwk, nil
+}
+
+// Thumbprint computes the RFC 7638 SHA-256 thumbprint of the public key
+func (k *SigningKey) Thumbprint() (string, error) {
+	jwk, err := k.JWK()
+	if err != nil {
+		return "", err
+	}
+
+	// Members must be in lexicographic order with no whitespace
+	var members any
+	switch jwk.KeyType {
+	case "RSA":
+		members = struct {
+			E   string `json:"e"`
+			Kty string `json:"kty"`
+			N   string `json:"n"`
+		}{jwk.E, jwk.KeyType, jwk.N}
+	case "EC":
+		members = struct {
+			Crv string `json:"crv"`
+			Kty string `json:"kty"`
+			X   string `json:"x"`
+			Y   string `json:"y"`
+		}{jwk.Curve, jwk.KeyType, jwk.X, jwk.Y}
+	default:
+		members = struct {
+			Crv string `json:"crv"`
+			Kty string `json:"kty"`
+			X   string `json:"x"`
+		}{jwk.Curve, jwk.KeyType, jwk.X}
+	}
+
+	raw, err := json.Marshal(members)
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(raw)
+	return encodeSegment(sum[:]), nil
+}
+
+func encodeSegment(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func bigEndian(v int) []byte {
+	var out []byte
+	for ; v > 0; v >>= 8 {
+		out = append([]byte{byte(v)}, out...)
+	}
+	return out
+}
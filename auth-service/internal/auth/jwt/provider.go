@@ -1,81 +1,272 @@
 package jwt
 
 import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
 	"time"
 
+	"github.com/natrayanp/GoMicro/auth-service/internal/config"
 	"github.com/natrayanp/GoMicro/auth-service/internal/domain"
 
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 )
 
+const defaultIssuer = "auth-service"
+
 type Provider struct {
-	secretKey     string
+	keys          *Keyring
 	accessExpiry  time.Duration
 	refreshExpiry time.Duration
+	issuer        string
+	audience      []string
+	pepper        []byte
+	keyDigest     []byte // digest of the key file the active key was loaded from
 }
 
+// NewJWTProvider creates an HS256 provider from a shared secret
 func NewJWTProvider(secret string, accessExp, refreshExp time.Duration) *Provider {
+	return NewJWTProviderWithKey(NewHMACKey(secret, ""), accessExp, refreshExp)
+}
+
+// NewJWTProviderWithKey creates a provider that signs with the given key.
+// Keys promoted later stay valid for verification for refreshExp.
+func NewJWTProviderWithKey(key *SigningKey, accessExp, refreshExp time.Duration) *Provider {
+	return NewJWTProviderWithKeyring(NewKeyring(key, refreshExp), accessExp, refreshExp)
+}
+
+// NewJWTProviderWithKeyring creates a provider backed by an existing keyring
+func NewJWTProviderWithKeyring(keys *Keyring, accessExp, refreshExp time.Duration) *Provider {
 	return &Provider{
-		secretKey:     secret,
+		keys:          keys,
 		accessExpiry:  accessExp,
 		refreshExpiry: refreshExp,
+		issuer:        defaultIssuer,
+		audience:      []string{defaultIssuer},
 	}
 }
 
-func (p *Provider) GenerateAccessToken(userID string) (string, error) {
-	claims := jwt.MapClaims{
-		"sub": userID,
-		"exp": time.Now().Add(p.accessExpiry).Unix(),
+// NewProviderFromConfig builds a provider for the configured signing method,
+// loading the private key from disk for asymmetric methods. Previous secrets
+// and retired key files are accepted for verification during the grace period.
+func NewProviderFromConfig(cfg *config.JWTConfig) (*Provider, error) {
+	key, err := loadConfiguredKey(cfg, cfg.KeyID)
+	if err != nil {
+		return nil, err
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(p.secretKey))
-}
+	keys := NewKeyring(key, cfg.KeyGracePeriod)
 
-func (p *Provider) GenerateRefreshToken(userID string) (string, error) {
-	claims := jwt.MapClaims{
-		"sub": userID,
-		"exp": time.Now().Add(p.refreshExpiry).Unix(),
+	for _, secret := range cfg.PreviousSecrets {
+		keys.Retire(NewHMACKey(secret, ""))
+	}
+
+	for _, path := range cfg.RetiredKeyFiles {
+		retired, err := LoadSigningKey(cfg.SigningMethod, path, "")
+		if err != nil {
+			return nil, fmt.Errorf("failed to load retired key: %w", err)
+		}
+		keys.Retire(retired)
+	}
+
+	provider := NewJWTProviderWithKeyring(keys, cfg.AccessExpiry, cfg.RefreshExpiry)
+	if cfg.Issuer != "" {
+		provider.issuer = cfg.Issuer
+	}
+	if len(cfg.Audience) > 0 {
+		provider.audience = cfg.Audience
+	}
+	provider.pepper = []byte(cfg.RefreshPepper)
+	if cfg.PrivateKeyFile != "" {
+		provider.keyDigest, _ = fileDigest(cfg.PrivateKeyFile)
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(p.secretKey))
+	return provider, nil
 }
 
-func (p *Provider) GenerateTokenPair(userID string) (*domain.TokenPair, error) {
-	access, err := p.GenerateAccessToken(userID)
+// Keyring exposes the provider's keys for runtime rotation
+func (p *Provider) Keyring() *Keyring {
+	return p.keys
+}
+
+func (p *Provider) GenerateAccessToken(userID string) (string, error) {
+	return p.GenerateAccessTokenFor(domain.TokenGrant{UserID: userID})
+}
+
+// GenerateAccessTokenFor issues an access token carrying the grant's
+// session, roles and scopes
+func (p *Provider) GenerateAccessTokenFor(grant domain.TokenGrant) (string, error) {
+	claims := p.newClaims(grant.UserID, domain.TokenTypeAccess, p.accessExpiry)
+	claims.SessionID = grant.SessionID
+	claims.Roles = grant.Roles
+	claims.Scope = strings.Join(grant.Scopes, " ")
+	return p.sign(claims)
+}
+
+func (p *Provider) GenerateRefreshToken(userID string) (string, error) {
+	return p.sign(p.newClaims(userID, domain.TokenTypeRefresh, p.refreshExpiry))
+}
+
+func (p *Provider) GenerateTokenPair(grant domain.TokenGrant) (*domain.TokenPair, error) {
+	access, err := p.GenerateAccessTokenFor(grant)
 	if err != nil {
 		return nil, err
 	}
 
-	refresh, err := p.GenerateRefreshToken(userID)
+	refreshClaims := p.newClaims(grant.UserID, domain.TokenTypeRefresh, p.refreshExpiry)
+	refreshClaims.SessionID = grant.SessionID
+	refresh, err := p.sign(refreshClaims)
 	if err != nil {
 		return nil, err
 	}
 
 	return &domain.TokenPair{
-		AccessToken:  access,
-		RefreshToken: refresh,
-		ExpiresIn:    int64(p.accessExpiry.Seconds()),
-		TokenType:    "Bearer",
+		AccessToken:      access,
+		RefreshToken:     refresh,
+		ExpiresIn:        int64(p.accessExpiry.Seconds()),
+		TokenType:        "Bearer",
+		RefreshExpiresAt: refreshClaims.ExpiresAt.Time,
 	}, nil
 }
 
-func (p *Provider) ValidateToken(tokenString string) (string, string, error) {
-	token, err := jwt.Parse(tokenString, func(t *jwt.Token) (interface{}, error) {
-		return []byte(p.secretKey), nil
-	})
+// AccessExpiry returns the lifetime of issued access tokens
+func (p *Provider) AccessExpiry() time.Duration {
+	return p.accessExpiry
+}
+
+// ValidateToken verifies the signature, issuer, audience and time claims
+// and returns the typed claim set. Callers must check Type themselves.
+func (p *Provider) ValidateToken(tokenString string) (*domain.TokenClaims, error) {
+	claims := &tokenClaims{}
 
-	if err != nil || !token.Valid {
-		return "", "", domain.ErrInvalidToken
+	token, err := jwt.ParseWithClaims(tokenString, claims, p.keyFunc,
+		jwt.WithIssuer(p.issuer),
+		jwt.WithAudience(p.audience...),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+	)
+	if err != nil {
+		if errors.Is(err, jwt.ErrTokenExpired) {
+			return nil, domain.ErrTokenExpired
+		}
+		return nil, domain.ErrInvalidToken
 	}
 
-	claims := token.Claims.(jwt.MapClaims)
-	sub := claims["sub"].(string)
+	if !token.Valid || claims.Subject == "" || claims.ID == "" {
+		return nil, domain.ErrInvalidToken
+	}
 
-	return sub, tokenString, nil
+	switch claims.Type {
+	case domain.TokenTypeAccess, domain.TokenTypeRefresh:
+	default:
+		return nil, domain.ErrInvalidToken
+	}
+
+	return claims.toDomain(), nil
 }
 
+// ParseRefreshToken verifies a JWT refresh token and returns how to find
+// it in storage. Access tokens are rejected.
+func (p *Provider) ParseRefreshToken(token string) (*domain.RefreshTokenLookup, error) {
+	claims, err := p.ValidateToken(token)
+	if err != nil {
+		return nil, err
+	}
+
+	if claims.Type != domain.TokenTypeRefresh {
+		return nil, domain.ErrWrongTokenType
+	}
+
+	return &domain.RefreshTokenLookup{TokenHash: p.HashRefreshToken(token)}, nil
+}
+
+// HashRefreshToken returns the hex encoded HMAC-SHA256 of the token keyed
+// with the server-side pepper, so a leaked refresh_tokens table can neither
+// be replayed nor brute-forced offline without the pepper
 func (p *Provider) HashRefreshToken(token string) string {
-	return token // replace with real hashing later
+	mac := hmac.New(sha256.New, p.pepper)
+	mac.Write([]byte(token))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// newClaims builds a claim set with a unique jti for the given token type
+func (p *Provider) newClaims(userID string, tokenType domain.TokenType, ttl time.Duration) *tokenClaims {
+	now := time.Now()
+
+	return &tokenClaims{
+		Type:           tokenType,
+		IssuedAtMicros: now.UnixMicro(),
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.NewString(),
+			Subject:   userID,
+			Issuer:    p.issuer,
+			Audience:  p.audience,
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
+		},
+	}
+}
+
+// sign stamps the key ID header and signs the claims with the active key
+func (p *Provider) sign(claims jwt.Claims) (string, error) {
+	key := p.keys.Active()
+
+	token := jwt.NewWithClaims(key.Method, claims)
+	token.Header["kid"] = key.ID
+	return token.SignedString(key.Private)
+}
+
+// keyFunc selects the verification key named by the token's kid header and
+// rejects tokens whose alg does not match that key. Tokens without a kid are
+// checked against the active key.
+func (p *Provider) keyFunc(t *jwt.Token) (interface{}, error) {
+	key := p.keys.Active()
+
+	if kid, _ := t.Header["kid"].(string); kid != "" {
+		found, ok := p.keys.Lookup(kid)
+		if !ok {
+			return nil, fmt.Errorf("unknown key id %q", kid)
+		}
+		key = found
+	}
+
+	if t.Method.Alg() != key.Method.Alg() {
+		return nil, fmt.Errorf("unexpected signing method %s", t.Method.Alg())
+	}
+
+	return key.Public, nil
+}
+
+// verificationKeys returns every key tokens may currently be verified with
+func (p *Provider) verificationKeys() []*SigningKey {
+	return p.keys.VerificationKeys()
+}
+
+// loadConfiguredKey loads the signing key described by cfg. For HS256 the
+// secret comes from PrivateKeyFile when set, otherwise from SecretKey.
+func loadConfiguredKey(cfg *config.JWTConfig, kid string) (*SigningKey, error) {
+	switch cfg.SigningMethod {
+	case "", jwt.SigningMethodHS256.Alg():
+		if cfg.PrivateKeyFile == "" {
+			return NewHMACKey(cfg.SecretKey, kid), nil
+		}
+
+		secret, err := os.ReadFile(cfg.PrivateKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read secret %s: %w", cfg.PrivateKeyFile, err)
+		}
+		return NewHMACKey(strings.TrimSpace(string(secret)), kid), nil
+
+	default:
+		if cfg.PrivateKeyFile == "" {
+			return nil, fmt.Errorf("JWT_PRIVATE_KEY_FILE is required for %s", cfg.SigningMethod)
+		}
+		return LoadSigningKey(cfg.SigningMethod, cfg.PrivateKeyFile, kid)
+	}
 }
@@ -0,0 +1,65 @@
+package jwt
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/natrayanp/GoMicro/auth-service/internal/config"
+)
+
+// WatchKeyFile polls the configured key file and promotes the key it
+// contains whenever the file changes, so keys can be rotated without a
+// restart. It blocks until ctx is cancelled and does nothing when no key
+// file or watch interval is configured.
+func (p *Provider) WatchKeyFile(ctx context.Context, cfg *config.JWTConfig) {
+	if cfg.PrivateKeyFile == "" || cfg.KeyWatchInterval <= 0 {
+		return
+	}
+
+	last := p.keyDigest
+
+	ticker := time.NewTicker(cfg.KeyWatchInterval)
+	defer ticker.Stop()
+
+	slog.Info("watching signing key for rotation", "file", cfg.PrivateKeyFile)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		digest, err := fileDigest(cfg.PrivateKeyFile)
+		if err != nil || bytes.Equal(digest, last) {
+			continue
+		}
+
+		// Rotated keys always get a derived kid; a configured JWT_KEY_ID
+		// only names the key the service started with.
+		key, err := loadConfiguredKey(cfg, "")
+		if err != nil {
+			// Possibly a partially written file; retry on the next tick
+			slog.Error("failed to load rotated signing key", "error", err)
+			continue
+		}
+
+		last = digest
+		p.keys.Promote(key)
+		slog.Info("promoted signing key", "kid", key.ID)
+	}
+}
+
+func fileDigest(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	sum := sha256.Sum256(data)
+	return sum[:], nil
+}
@@ -0,0 +1,96 @@
+package opaque
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"strings"
+	"time"
+
+	"github.com/natrayanp/GoMicro/auth-service/internal/auth/jwt"
+	"github.com/natrayanp/GoMicro/auth-service/internal/domain"
+)
+
+const (
+	selectorBytes = 16 // non-secret lookup key
+	verifierBytes = 32 // 256-bit secret, only ever stored hashed
+)
+
+// Provider issues JWT access tokens and opaque random refresh tokens.
+// A refresh token is "<selector>.<verifier>": storage finds the row by
+// selector and compares the peppered hash in constant time, so refresh
+// tokens carry no claims and are only valid while their row is.
+type Provider struct {
+	*jwt.Provider
+	refreshExpiry time.Duration
+}
+
+// NewProvider wraps a JWT provider that keeps issuing the access tokens
+func NewProvider(accessTokens *jwt.Provider, refreshExp time.Duration) *Provider {
+	return &Provider{
+		Provider:      accessTokens,
+		refreshExpiry: refreshExp,
+	}
+}
+
+// GenerateRefreshToken returns a new random refresh token. The user ID is
+// not embedded; it lives in the refresh_tokens row.
+func (p *Provider) GenerateRefreshToken(userID string) (string, error) {
+	selector, err := randomSegment(selectorBytes)
+	if err != nil {
+		return "", err
+	}
+
+	verifier, err := randomSegment(verifierBytes)
+	if err != nil {
+		return "", err
+	}
+
+	return selector + "." + verifier, nil
+}
+
+func (p *Provider) GenerateTokenPair(grant domain.TokenGrant) (*domain.TokenPair, error) {
+	access, err := p.GenerateAccessTokenFor(grant)
+	if err != nil {
+		return nil, err
+	}
+
+	refresh, err := p.GenerateRefreshToken(grant.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.TokenPair{
+		AccessToken:      access,
+		RefreshToken:     refresh,
+		ExpiresIn:        int64(p.AccessExpiry().Seconds()),
+		TokenType:        "Bearer",
+		RefreshExpiresAt: time.Now().Add(p.refreshExpiry),
+	}, nil
+}
+
+// ParseRefreshToken splits an opaque refresh token into its selector and
+// the hash storage must match. JWTs and malformed strings are rejected.
+func (p *Provider) ParseRefreshToken(token string) (*domain.RefreshTokenLookup, error) {
+	selector, verifier, ok := strings.Cut(token, ".")
+	if !ok || !validSegment(selector, selectorBytes) || !validSegment(verifier, verifierBytes) {
+		return nil, domain.ErrInvalidToken
+	}
+
+	return &domain.RefreshTokenLookup{
+		Selector:  selector,
+		TokenHash: p.HashRefreshToken(token),
+	}, nil
+}
+
+func randomSegment(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func validSegment(segment string, n int) bool {
+	b, err := base64.RawURLEncoding.DecodeString(segment)
+	return err == nil && len(b) == n
+}
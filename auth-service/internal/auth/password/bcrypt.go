@@ -0,0 +1,60 @@
+package password
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/natrayanp/GoMicro/auth-service/internal/domain"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// BcryptMaxBytes is the longest password bcrypt accepts, in bytes
+const BcryptMaxBytes = 72
+
+// Bcrypt hashes passwords with bcrypt. Hashes use bcrypt's own modular
+// crypt format, e.g. "$2a$10$<salt+hash>".
+type Bcrypt struct {
+	Cost int
+}
+
+func (b Bcrypt) matches(encodedHash string) bool {
+	for _, prefix := range []string{"$2a$", "$2b$", "$2y$"} {
+		if strings.HasPrefix(encodedHash, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func (b Bcrypt) hash(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), b.Cost)
+	if errors.Is(err, bcrypt.ErrPasswordTooLong) {
+		// Normally caught by the policy first; report it the same way
+		return "", domain.NewPasswordPolicyError([]domain.PasswordViolation{{
+			Rule:    domain.PasswordRuleMaxLength,
+			Message: fmt.Sprintf("password must be at most %d bytes", BcryptMaxBytes),
+		}})
+	}
+	if err != nil {
+		return "", err
+	}
+	return string(hash), nil
+}
+
+func (b Bcrypt) verify(password, encodedHash string) (bool, error) {
+	err := bcrypt.CompareHashAndPassword([]byte(encodedHash), []byte(password))
+	if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (b Bcrypt) needsRehash(encodedHash string) bool {
+	cost, err := bcrypt.Cost([]byte(encodedHash))
+	return err != nil || cost != b.Cost
+}
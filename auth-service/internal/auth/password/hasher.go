@@ -0,0 +1,101 @@
+// Package password hashes user passwords with bcrypt or Argon2id.
+package password
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/natrayanp/GoMicro/auth-service/internal/config"
+	"github.com/natrayanp/GoMicro/auth-service/internal/ports"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// ErrMalformedHash is returned for stored hashes no algorithm recognizes
+var ErrMalformedHash = errors.New("malformed password hash")
+
+// Algorithm is a password hashing scheme, either Bcrypt or Argon2id
+type Algorithm interface {
+	matches(encodedHash string) bool
+	hash(password string) (string, error)
+	verify(password, encodedHash string) (bool, error)
+	needsRehash(encodedHash string) bool
+}
+
+// Hasher hashes new passwords with its preferred algorithm and verifies
+// hashes from any supported algorithm, so stored hashes can be upgraded
+// on the next successful login
+type Hasher struct {
+	preferred Algorithm
+	fallbacks []Algorithm
+}
+
+var _ ports.PasswordHasher = (*Hasher)(nil)
+
+// NewHasher creates a hasher preferring the given algorithm. Bcrypt and
+// Argon2id hashes are always verifiable.
+func NewHasher(preferred Algorithm) *Hasher {
+	return &Hasher{
+		preferred: preferred,
+		fallbacks: []Algorithm{Argon2id{}, Bcrypt{}},
+	}
+}
+
+// NewHasherFromConfig creates the hasher selected by PASSWORD_HASH_ALGORITHM
+func NewHasherFromConfig(cfg *config.PasswordConfig) (*Hasher, error) {
+	switch cfg.Algorithm {
+	case "argon2id":
+		if cfg.Argon2Iterations < 1 || cfg.Argon2Parallelism < 1 || cfg.Argon2Memory < 8*uint32(cfg.Argon2Parallelism) {
+			return nil, fmt.Errorf("invalid Argon2id parameters m=%d t=%d p=%d", cfg.Argon2Memory, cfg.Argon2Iterations, cfg.Argon2Parallelism)
+		}
+		return NewHasher(Argon2id{
+			Memory:      cfg.Argon2Memory,
+			Iterations:  cfg.Argon2Iterations,
+			Parallelism: cfg.Argon2Parallelism,
+			SaltLength:  DefaultArgon2id.SaltLength,
+			KeyLength:   DefaultArgon2id.KeyLength,
+		}), nil
+	case "bcrypt":
+		if cfg.BcryptCost < bcrypt.MinCost || cfg.BcryptCost > bcrypt.MaxCost {
+			return nil, fmt.Errorf("bcrypt cost must be between %d and %d", bcrypt.MinCost, bcrypt.MaxCost)
+		}
+		return NewHasher(Bcrypt{Cost: cfg.BcryptCost}), nil
+	default:
+		return nil, fmt.Errorf("unsupported password hash algorithm %q", cfg.Algorithm)
+	}
+}
+
+// Hash implements PasswordHasher.Hash
+func (h *Hasher) Hash(password string) (string, error) {
+	return h.preferred.hash(password)
+}
+
+// Verify implements PasswordHasher.Verify
+func (h *Hasher) Verify(password, encodedHash string) (bool, error) {
+	algorithm, ok := h.algorithmFor(encodedHash)
+	if !ok {
+		return false, ErrMalformedHash
+	}
+	return algorithm.verify(password, encodedHash)
+}
+
+// NeedsRehash reports whether a stored hash uses another algorithm or
+// different parameters than new hashes would
+func (h *Hasher) NeedsRehash(encodedHash string) bool {
+	if !h.preferred.matches(encodedHash) {
+		return true
+	}
+	return h.preferred.needsRehash(encodedHash)
+}
+
+func (h *Hasher) algorithmFor(encodedHash string) (Algorithm, bool) {
+	if h.preferred.matches(encodedHash) {
+		return h.preferred, true
+	}
+	for _, algorithm := range h.fallbacks {
+		if algorithm.matches(encodedHash) {
+			return algorithm, true
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,95 @@
+package password
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/natrayanp/GoMicro/auth-service/internal/config"
+	"github.com/natrayanp/GoMicro/auth-service/internal/domain"
+	"github.com/natrayanp/GoMicro/auth-service/internal/ports"
+)
+
+// Policy applies the domain password rules plus a list of common or
+// breached passwords that are never accepted
+type Policy struct {
+	rules  domain.PasswordPolicy
+	common map[string]struct{}
+}
+
+var _ ports.PasswordPolicy = (*Policy)(nil)
+
+// NewPolicy creates a policy. common may be nil.
+func NewPolicy(rules domain.PasswordPolicy, common map[string]struct{}) *Policy {
+	return &Policy{rules: rules, common: common}
+}
+
+// NewPolicyFromConfig creates the policy from PASSWORD_* settings, loading
+// the common password list when a file is configured. With bcrypt passwords
+// are also limited to BcryptMaxBytes.
+func NewPolicyFromConfig(cfg *config.PasswordConfig) (*Policy, error) {
+	var common map[string]struct{}
+	if cfg.CommonPasswordsFile != "" {
+		var err error
+		if common, err = LoadCommonPasswords(cfg.CommonPasswordsFile); err != nil {
+			return nil, err
+		}
+	}
+
+	var maxBytes int
+	if cfg.Algorithm == "bcrypt" {
+		maxBytes = BcryptMaxBytes
+	}
+
+	return NewPolicy(domain.PasswordPolicy{
+		MinLength:        cfg.MinLength,
+		MaxLength:        cfg.MaxLength,
+		MaxBytes:         maxBytes,
+		RequireUppercase: cfg.RequireUppercase,
+		RequireLowercase: cfg.RequireLowercase,
+		RequireDigit:     cfg.RequireDigit,
+		RequireSymbol:    cfg.RequireSymbol,
+		DisallowEmail:    cfg.DisallowEmail,
+	}, common), nil
+}
+
+// Check implements PasswordPolicy.Check
+func (p *Policy) Check(password, email string) error {
+	violations := p.rules.Violations(password, email)
+
+	if _, found := p.common[strings.ToLower(password)]; found {
+		violations = append(violations, domain.PasswordViolation{
+			Rule:    domain.PasswordRuleCommon,
+			Message: "password is too common",
+		})
+	}
+
+	return domain.NewPasswordPolicyError(violations)
+}
+
+// LoadCommonPasswords reads one password per line. Blank lines and lines
+// starting with # are skipped; matching is case-insensitive.
+func LoadCommonPasswords(path string) (map[string]struct{}, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open common password list: %w", err)
+	}
+	defer file.Close()
+
+	common := make(map[string]struct{})
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		common[strings.ToLower(line)] = struct{}{}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read common password list: %w", err)
+	}
+
+	return common, nil
+}
@@ -3,51 +3,186 @@ package config
 import (
     "os"
     "strconv"
+    "strings"
     "time"
 )
 
 type Config struct {
-    Server   ServerConfig
-    Database DatabaseConfig
-    JWT      JWTConfig
+    Server          ServerConfig
+    Database        DatabaseConfig
+    JWT             JWTConfig
+    Auth            AuthConfig
+    ValidationCache ValidationCacheConfig
+    Password        PasswordConfig
+    Lockout         LockoutConfig
+    RateLimit       RateLimitConfig
+    Tracing         TracingConfig
+    Log             LogConfig
 }
 
 type ServerConfig struct {
-    Port int
-    Host string
+    Port       int
+    Host       string
+    Reflection bool // register gRPC server reflection, callable without a token
 }
 
 type DatabaseConfig struct {
-    Host     string
-    Port     int
-    User     string
-    Password string
-    Name     string
+    Driver      string // "postgres" or "memory" (local dev only, nothing persists)
+    Host        string
+    Port        int
+    User        string
+    Password    string
+    Name        string
+    AutoMigrate bool // apply pending migrations on startup
+}
+
+type AuthConfig struct {
+    AdminUserIDs []string // users allowed to call every method, including other users' records
+    TokenScopes  []string // scopes granted to every access token
+}
+
+type ValidationCacheConfig struct {
+    Size int           // access tokens kept; 0 disables the cache
+    TTL  time.Duration // upper bound on how long a validation is reused, at most tokencache.MaxTTL
+}
+
+type PasswordConfig struct {
+    Algorithm         string // argon2id or bcrypt, used for new hashes
+    BcryptCost        int
+    Argon2Memory      uint32 // KiB
+    Argon2Iterations  uint32
+    Argon2Parallelism uint8
+
+    MinLength           int
+    MaxLength           int
+    RequireUppercase    bool
+    RequireLowercase    bool
+    RequireDigit        bool
+    RequireSymbol       bool
+    DisallowEmail       bool   // reject passwords containing the email local-part
+    CommonPasswordsFile string // one password per line; empty disables the check
+    HistorySize         int    // previous passwords that cannot be reused; 0 disables
+}
+
+type LockoutConfig struct {
+    MaxAttempts  int           // failed logins before the first lock; 0 disables lockout
+    BaseDuration time.Duration // first lock, doubled on every further failure
+    MaxDuration  time.Duration
+    ResetAfter   time.Duration // failures older than this no longer count
+}
+
+type RateLimitConfig struct {
+    Enabled bool
+    Rules   string // comma-separated Method=key:count/period, key is ip, email or principal
+}
+
+type TracingConfig struct {
+    Enabled     bool   // export spans over OTLP; incoming trace context is passed on either way
+    Endpoint    string // OTLP gRPC collector, host:port
+    Insecure    bool   // plaintext connection to the collector
+    ServiceName string
+    SampleRatio float64 // fraction of new traces sampled; callers' decisions are kept
+}
+
+type LogConfig struct {
+    Level  string // debug, info, warn or error
+    Format string // json or text
 }
 
 type JWTConfig struct {
-    SecretKey     string
-    AccessExpiry  time.Duration
-    RefreshExpiry time.Duration
+    SecretKey        string
+    SigningMethod    string // HS256, RS256, ES256 or EdDSA
+    PrivateKeyFile   string // PEM private key (or HS256 secret file), required for asymmetric methods
+    KeyID            string // optional; derived from the key when empty
+    AccessExpiry     time.Duration
+    RefreshExpiry    time.Duration
+    KeyGracePeriod   time.Duration // how long retired keys still verify tokens
+    KeyWatchInterval time.Duration // poll PrivateKeyFile for rotation; 0 disables
+    RetiredKeyFiles  []string      // previous PEM keys still accepted for verification
+    PreviousSecrets  []string      // previous HS256 secrets still accepted for verification
+    Issuer           string
+    Audience         []string
+    RefreshPepper    string // server-side HMAC key for stored refresh token hashes
+    RefreshFormat    string // "jwt" or "opaque" refresh tokens
 }
 
 func Load() *Config {
     return &Config{
         Server: ServerConfig{
-            Port: getEnvAsInt("SERVER_PORT", 50051),
-            Host: getEnv("SERVER_HOST", "0.0.0.0"),
+            Port:       getEnvAsInt("SERVER_PORT", 50051),
+            Host:       getEnv("SERVER_HOST", "0.0.0.0"),
+            Reflection: getEnvAsBool("SERVER_REFLECTION", false),
         },
         Database: DatabaseConfig{
-            Host:     getEnv("DB_HOST", "localhost"),
-            Port:     getEnvAsInt("DB_PORT", 5432),
-            User:     getEnv("DB_USER", "postgres"),
-            Password: getEnv("DB_PASSWORD", "password"),
-            Name:     getEnv("DB_NAME", "auth_service"),
+            Driver:      getEnv("DB_DRIVER", "postgres"),
+            Host:        getEnv("DB_HOST", "localhost"),
+            Port:        getEnvAsInt("DB_PORT", 5432),
+            User:        getEnv("DB_USER", "postgres"),
+            Password:    getEnv("DB_PASSWORD", "password"),
+            Name:        getEnv("DB_NAME", "auth_service"),
+            AutoMigrate: getEnvAsBool("DB_AUTO_MIGRATE", false),
         },
         JWT: JWTConfig{
-            SecretKey:     getEnv("JWT_SECRET", "default-secret-key"),
-            AccessExpiry:  getEnvAsDuration("JWT_ACCESS_EXPIRY", 15*time.Minute),
-            RefreshExpiry: getEnvAsDuration("JWT_REFRESH_EXPIRY", 168*time.Hour), // 7 days
+            SecretKey:        getEnv("JWT_SECRET", "default-secret-key"),
+            SigningMethod:    getEnv("JWT_SIGNING_METHOD", "HS256"),
+            PrivateKeyFile:   getEnv("JWT_PRIVATE_KEY_FILE", ""),
+            KeyID:            getEnv("JWT_KEY_ID", ""),
+            AccessExpiry:     getEnvAsDuration("JWT_ACCESS_EXPIRY", 15*time.Minute),
+            RefreshExpiry:    getEnvAsDuration("JWT_REFRESH_EXPIRY", 168*time.Hour), // 7 days
+            KeyGracePeriod:   getEnvAsDuration("JWT_KEY_GRACE_PERIOD", 168*time.Hour),
+            KeyWatchInterval: getEnvAsDuration("JWT_KEY_WATCH_INTERVAL", 0),
+            RetiredKeyFiles:  getEnvAsSlice("JWT_RETIRED_KEY_FILES", nil),
+            PreviousSecrets:  getEnvAsSlice("JWT_PREVIOUS_SECRETS", nil),
+            Issuer:           getEnv("JWT_ISSUER", "auth-service"),
+            Audience:         getEnvAsSlice("JWT_AUDIENCE", []string{"auth-service"}),
+            RefreshPepper:    getEnv("JWT_REFRESH_TOKEN_PEPPER", "default-refresh-token-pepper"),
+            RefreshFormat:    getEnv("JWT_REFRESH_TOKEN_FORMAT", "jwt"),
+        },
+        Auth: AuthConfig{
+            AdminUserIDs: getEnvAsSlice("AUTH_ADMIN_USER_IDS", nil),
+            TokenScopes:  getEnvAsSlice("AUTH_TOKEN_SCOPES", nil),
+        },
+        ValidationCache: ValidationCacheConfig{
+            Size: getEnvAsInt("VALIDATION_CACHE_SIZE", 0),
+            TTL:  getEnvAsDuration("VALIDATION_CACHE_TTL", 10*time.Second),
+        },
+        Password: PasswordConfig{
+            Algorithm:         getEnv("PASSWORD_HASH_ALGORITHM", "argon2id"),
+            BcryptCost:        getEnvAsInt("PASSWORD_BCRYPT_COST", 12),
+            Argon2Memory:      uint32(getEnvAsInt("PASSWORD_ARGON2_MEMORY_KIB", 19*1024)),
+            Argon2Iterations:  uint32(getEnvAsInt("PASSWORD_ARGON2_ITERATIONS", 2)),
+            Argon2Parallelism: uint8(getEnvAsInt("PASSWORD_ARGON2_PARALLELISM", 1)),
+
+            MinLength:           getEnvAsInt("PASSWORD_MIN_LENGTH", 8),
+            MaxLength:           getEnvAsInt("PASSWORD_MAX_LENGTH", 128),
+            RequireUppercase:    getEnvAsBool("PASSWORD_REQUIRE_UPPERCASE", false),
+            RequireLowercase:    getEnvAsBool("PASSWORD_REQUIRE_LOWERCASE", false),
+            RequireDigit:        getEnvAsBool("PASSWORD_REQUIRE_DIGIT", false),
+            RequireSymbol:       getEnvAsBool("PASSWORD_REQUIRE_SYMBOL", false),
+            DisallowEmail:       getEnvAsBool("PASSWORD_DISALLOW_EMAIL", true),
+            CommonPasswordsFile: getEnv("PASSWORD_COMMON_LIST_FILE", ""),
+            HistorySize:         getEnvAsInt("PASSWORD_HISTORY_SIZE", 5),
+        },
+        Lockout: LockoutConfig{
+            MaxAttempts:  getEnvAsInt("LOCKOUT_MAX_ATTEMPTS", 5),
+            BaseDuration: getEnvAsDuration("LOCKOUT_BASE_DURATION", time.Minute),
+            MaxDuration:  getEnvAsDuration("LOCKOUT_MAX_DURATION", time.Hour),
+            ResetAfter:   getEnvAsDuration("LOCKOUT_RESET_AFTER", 24*time.Hour),
+        },
+        RateLimit: RateLimitConfig{
+            Enabled: getEnvAsBool("RATE_LIMIT_ENABLED", true),
+            Rules:   getEnv("RATE_LIMIT_RULES", "Register=ip:10/1m,Login=ip:30/1m,Login=email:10/1m,Refresh=ip:60/1m,*=ip:600/1m"),
+        },
+        Tracing: TracingConfig{
+            Enabled:     getEnvAsBool("TRACING_ENABLED", false),
+            Endpoint:    getEnv("TRACING_OTLP_ENDPOINT", "localhost:4317"),
+            Insecure:    getEnvAsBool("TRACING_OTLP_INSECURE", true),
+            ServiceName: getEnv("TRACING_SERVICE_NAME", "auth-service"),
+            SampleRatio: getEnvAsFloat("TRACING_SAMPLE_RATIO", 1),
+        },
+        Log: LogConfig{
+            Level:  getEnv("LOG_LEVEL", "info"),
+            Format: getEnv("LOG_FORMAT", "json"),
         },
     }
 }
@@ -68,6 +203,24 @@ func getEnvAsInt(key string, defaultValue int) int {
     return defaultValue
 }
 
+func getEnvAsFloat(key string, defaultValue float64) float64 {
+    if value, exists := os.LookupEnv(key); exists {
+        if floatValue, err := strconv.ParseFloat(value, 64); err == nil {
+            return floatValue
+        }
+    }
+    return defaultValue
+}
+
+func getEnvAsBool(key string, defaultValue bool) bool {
+    if value, exists := os.LookupEnv(key); exists {
+        if boolValue, err := strconv.ParseBool(value); err == nil {
+            return boolValue
+        }
+    }
+    return defaultValue
+}
+
 func getEnvAsDuration(key string, defaultValue time.Duration) time.Duration {
     if value, exists := os.LookupEnv(key); exists {
         if duration, err := time.ParseDuration(value); err == nil {
@@ -75,4 +228,17 @@ func getEnvAsDuration(key string, defaultValue time.Duration) time.Duration {
         }
     }
     return defaultValue
+}
+
+func getEnvAsSlice(key string, defaultValue []string) []string {
+    if value, exists := os.LookupEnv(key); exists {
+        var items []string
+        for _, item := range strings.Split(value, ",") {
+            if item = strings.TrimSpace(item); item != "" {
+                items = append(items, item)
+            }
+        }
+        return items
+    }
+    return defaultValue
 }
\ No newline at end of file
@@ -2,11 +2,18 @@ package core
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"sync"
 	"time"
 
+	"github.com/natrayanp/GoMicro/auth-service/internal/auth/password"
 	"github.com/natrayanp/GoMicro/auth-service/internal/domain"
+	"github.com/natrayanp/GoMicro/auth-service/internal/logging"
 	"github.com/natrayanp/GoMicro/auth-service/internal/ports"
 
+	"github.com/google/uuid"
 	"golang.org/x/crypto/bcrypt"
 )
 
@@ -16,6 +23,28 @@ type AuthService struct {
 	tokenRepo      ports.TokenRepository
 	tokenProvider  ports.TokenProviderPort
 	eventPublisher ports.EventPublisherPort // optional
+	uow            ports.UnitOfWork
+	passwordHasher ports.PasswordHasher
+	passwordPolicy ports.PasswordPolicy
+
+	dummyHashOnce sync.Once
+	dummyHash     string // verified against for unknown emails
+
+	passwordHistory     ports.PasswordHistoryRepository // optional
+	passwordHistorySize int
+
+	loginAttempts ports.LoginAttemptRepository // optional, enables lockout
+	lockout       domain.LockoutPolicy
+
+	metrics ports.MetricsRecorder
+
+	validationCache ports.ValidationCache // optional
+
+	accessDenylist ports.AccessTokenDenylistRepository // optional, enables access token revocation
+	accessExpiry   time.Duration
+
+	admins map[string]bool // user IDs whose access tokens carry RoleAdmin
+	scopes []string        // granted to every access token
 }
 
 func NewAuthService(
@@ -23,19 +52,52 @@ func NewAuthService(
 	tokenRepo ports.TokenRepository,
 	tokenProvider ports.TokenProviderPort,
 	eventPublisher ports.EventPublisherPort,
+	opts ...Option,
 ) *AuthService {
-	return &AuthService{
+	s := &AuthService{
 		userRepo:       userRepo,
 		tokenRepo:      tokenRepo,
 		tokenProvider:  tokenProvider,
 		eventPublisher: eventPublisher,
 	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	if s.passwordHasher == nil {
+		s.passwordHasher = password.NewHasher(password.Bcrypt{Cost: bcrypt.DefaultCost})
+	}
+
+	if s.passwordPolicy == nil {
+		s.passwordPolicy = domain.DefaultPasswordPolicy
+	}
+
+	if s.metrics == nil {
+		s.metrics = noopMetrics{}
+	}
+
+	if s.uow == nil {
+		s.uow = directUnitOfWork{repos: ports.TxRepositories{
+			Users:           userRepo,
+			Tokens:          tokenRepo,
+			PasswordHistory: s.passwordHistory,
+		}}
+	}
+
+	return s
 }
 
 // Register implements AuthServicePort.Register
-func (s *AuthService) Register(ctx context.Context, email, password string) (*domain.User, error) {
+func (s *AuthService) Register(ctx context.Context, email, password string) (_ *domain.User, err error) {
+	ctx, span := startSpan(ctx, "AuthService.Register")
+	defer func() { endSpan(span, err) }()
+
 	// Check if user exists
-	existing, _ := s.userRepo.GetUserByEmail(ctx, email)
+	existing, err := s.userRepo.GetUserByEmail(ctx, email)
+	if err != nil && !errors.Is(err, domain.ErrUserNotFound) {
+		return nil, err
+	}
 	if existing != nil {
 		return nil, domain.ErrUserExists
 	}
@@ -45,27 +107,30 @@ func (s *AuthService) Register(ctx context.Context, email, password string) (*do
 		return nil, err
 	}
 
-	if err := domain.ValidatePassword(password); err != nil {
+	if err := s.passwordPolicy.Check(password, email); err != nil {
 		return nil, err
 	}
 
 	// Hash password
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	hashedPassword, err := s.hashPassword(ctx, password)
 	if err != nil {
 		return nil, err
 	}
 
 	// Create user
-	user, err := domain.NewUser(email, string(hashedPassword))
+	user, err := domain.NewUser(email, hashedPassword)
 	if err != nil {
 		return nil, err
 	}
+	user.PasswordHash = hashedPassword
 
 	// Save to database
 	if err := s.userRepo.CreateUser(ctx, user); err != nil {
 		return nil, err
 	}
 
+	s.metrics.UserRegistered()
+
 	// Publish event if publisher exists
 	if s.eventPublisher != nil {
 		go s.eventPublisher.PublishUserRegistered(context.Background(), user)
@@ -75,29 +140,68 @@ func (s *AuthService) Register(ctx context.Context, email, password string) (*do
 }
 
 // Login implements AuthServicePort.Login
-func (s *AuthService) Login(ctx context.Context, email, password string) (*domain.TokenPair, error) {
+func (s *AuthService) Login(ctx context.Context, email, password string) (_ *domain.TokenPair, err error) {
+	ctx, span := startSpan(ctx, "AuthService.Login")
+	defer func() { endSpan(span, err) }()
+
+	tokenPair, err := s.login(ctx, email, password)
+	s.metrics.LoginAttempted(loginResult(err))
+	return tokenPair, err
+}
+
+func (s *AuthService) login(ctx context.Context, email, password string) (*domain.TokenPair, error) {
+	// Refuse locked addresses before spending time on the hash. Lockout is
+	// keyed on the email, so unknown addresses lock out like real accounts.
+	key := domain.LoginKey(email)
+	attempts, err := s.checkLockout(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+
 	// Find user
 	user, err := s.userRepo.GetUserByEmail(ctx, email)
+	if errors.Is(err, domain.ErrUserNotFound) {
+		// Do the same hash work as for a real account so response times
+		// do not reveal which emails are registered
+		_, _ = s.verifyPassword(ctx, password, s.dummyPasswordHash())
+		return nil, s.recordFailedLogin(ctx, key, "")
+	}
 	if err != nil {
-		return nil, domain.ErrInvalidCredentials
+		return nil, err
 	}
 
 	// Verify password
-	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password)); err != nil {
-		return nil, domain.ErrInvalidCredentials
+	ok, err := s.verifyPassword(ctx, password, user.PasswordHash)
+	if err != nil || !ok {
+		return nil, s.recordFailedLogin(ctx, key, user.ID)
+	}
+
+	if attempts != nil && attempts.FailedCount > 0 {
+		if err := s.loginAttempts.ResetLoginAttempts(ctx, key); err != nil {
+			return nil, err
+		}
+	}
+
+	// Upgrade hashes made with an outdated algorithm or cost while the
+	// plaintext is at hand
+	if s.passwordHasher.NeedsRehash(user.PasswordHash) {
+		s.rehashPassword(ctx, user.ID, password)
 	}
 
-	// Generate tokens
-	tokenPair, err := s.tokenProvider.GenerateTokenPair(user.ID)
+	// Generate tokens; each login is a new session and token family
+	grant := s.grantFor(user.ID, uuid.NewString())
+	tokenPair, err := s.tokenProvider.GenerateTokenPair(grant)
 	if err != nil {
 		return nil, err
 	}
 
-	// Hash and store refresh token
-	refreshTokenHash := s.tokenProvider.HashRefreshToken(tokenPair.RefreshToken)
-	expiresAt := time.Now().Add(7 * 24 * time.Hour) // 7 days
+	// Hash and store refresh token (starts a new token family)
+	token, err := s.newRefreshTokenRecord(grant, tokenPair, nil)
+	if err != nil {
+		return nil, err
+	}
 
-	if err := s.tokenRepo.CreateRefreshToken(ctx, user.ID, refreshTokenHash, expiresAt); err != nil {
+	if err := s.tokenRepo.CreateRefreshToken(ctx, token); err != nil {
 		return nil, err
 	}
 
@@ -110,127 +214,197 @@ func (s *AuthService) Login(ctx context.Context, email, password string) (*domai
 }
 
 // ValidateToken implements AuthServicePort.ValidateToken
-func (s *AuthService) ValidateToken(ctx context.Context, token string) (string, error) {
-	userID, tokenType, err := s.tokenProvider.ValidateToken(token)
-	if err != nil {
-		return "", err
-	}
-
-	if tokenType != "access" {
-		return "", domain.ErrInvalidToken
-	}
+func (s *AuthService) ValidateToken(ctx context.Context, token string) (_ *domain.TokenClaims, err error) {
+	ctx, span := startSpan(ctx, "AuthService.ValidateToken")
+	defer func() { endSpan(span, err) }()
 
-	return userID, nil
+	return s.validateAccessToken(ctx, token)
 }
 
 // RefreshToken implements AuthServicePort.RefreshToken
-func (s *AuthService) RefreshToken(ctx context.Context, refreshToken string) (*domain.TokenPair, error) {
-	// Validate refresh token
-	userID, tokenType, err := s.tokenProvider.ValidateToken(refreshToken)
+func (s *AuthService) RefreshToken(ctx context.Context, refreshToken string) (_ *domain.TokenPair, err error) {
+	ctx, span := startSpan(ctx, "AuthService.RefreshToken")
+	defer func() { endSpan(span, err) }()
+
+	// Check if token exists in database and is not revoked
+	dbToken, err := s.findRefreshToken(ctx, refreshToken)
 	if err != nil {
 		return nil, err
 	}
+	userID := dbToken.UserID
 
-	if tokenType != "refresh" {
-		return nil, domain.ErrInvalidToken
-	}
-
-	// Check if token exists in database and is not revoked
-	tokenHash := s.tokenProvider.HashRefreshToken(refreshToken)
-	dbToken, err := s.tokenRepo.GetRefreshToken(ctx, tokenHash)
-	if err != nil {
-		return nil, domain.ErrInvalidToken
+	// A rotated token must never come back; whoever presents it holds a
+	// stale copy, so assume theft and end the whole session
+	if dbToken.IsRotated() {
+		return nil, s.handleRefreshTokenReuse(ctx, dbToken)
 	}
 
 	if dbToken.IsExpired() {
-		return nil, domain.ErrTokenExpired
+		return nil, domain.ErrTokenExpired.WithMetadata(map[string]string{
+			"expired_at": dbToken.ExpiresAt.UTC().Format(time.RFC3339),
+		})
 	}
 
 	if dbToken.IsRevoked() {
 		return nil, domain.ErrTokenRevoked
 	}
 
-	// Generate new token pair
-	tokenPair, err := s.tokenProvider.GenerateTokenPair(userID)
+	// Generate new token pair for the same session
+	grant := s.grantFor(userID, dbToken.FamilyID)
+	tokenPair, err := s.tokenProvider.GenerateTokenPair(grant)
 	if err != nil {
 		return nil, err
 	}
 
-	// Store new refresh token
-	newRefreshTokenHash := s.tokenProvider.HashRefreshToken(tokenPair.RefreshToken)
-	expiresAt := time.Now().Add(7 * 24 * time.Hour)
-
-	if err := s.tokenRepo.CreateRefreshToken(ctx, userID, newRefreshTokenHash, expiresAt); err != nil {
+	next, err := s.newRefreshTokenRecord(grant, tokenPair, dbToken)
+	if err != nil {
 		return nil, err
 	}
 
-	// Revoke old refresh token
-	if err := s.tokenRepo.RevokeRefreshToken(ctx, tokenHash); err != nil {
-		// Log error but continue
+	// Revoke the old token and store its successor atomically. A token
+	// rotated or revoked since it was read gets ErrTokenRevoked; that is a
+	// lost race between concurrent refreshes or a logout, not reuse, so the
+	// family and the winner's new token are left alone.
+	if err := s.tokenRepo.RotateRefreshToken(ctx, dbToken.ID, next); err != nil {
+		return nil, err
 	}
 
 	return tokenPair, nil
 }
 
 // RevokeToken implements AuthServicePort.RevokeToken
-func (s *AuthService) RevokeToken(ctx context.Context, refreshToken string) error {
-	tokenHash := s.tokenProvider.HashRefreshToken(refreshToken)
+func (s *AuthService) RevokeToken(ctx context.Context, refreshToken string) (err error) {
+	ctx, span := startSpan(ctx, "AuthService.RevokeToken")
+	defer func() { endSpan(span, err) }()
 
-	err := s.tokenRepo.RevokeRefreshToken(ctx, tokenHash)
+	dbToken, err := s.findRefreshToken(ctx, refreshToken)
 	if err != nil {
 		return err
 	}
 
+	if err := s.tokenRepo.RevokeRefreshToken(ctx, dbToken.TokenHash); err != nil {
+		return err
+	}
+	s.tokensRevoked(dbToken.UserID, ports.RevokeLogout)
+
 	// Publish event
 	if s.eventPublisher != nil {
-		// We need to get user ID from token
-		userID, _, _ := s.tokenProvider.ValidateToken(refreshToken)
-		if userID != "" {
-			go s.eventPublisher.PublishUserLoggedOut(context.Background(), userID)
-		}
+		go s.eventPublisher.PublishUserLoggedOut(context.Background(), dbToken.UserID)
 	}
 
 	return nil
 }
 
 // RevokeAllUserTokens implements AuthServicePort.RevokeAllUserTokens
-func (s *AuthService) RevokeAllUserTokens(ctx context.Context, userID string) error {
-	return s.tokenRepo.RevokeAllUserTokens(ctx, userID)
+func (s *AuthService) RevokeAllUserTokens(ctx context.Context, userID string) (err error) {
+	ctx, span := startSpan(ctx, "AuthService.RevokeAllUserTokens")
+	defer func() { endSpan(span, err) }()
+
+	if err := s.tokenRepo.RevokeAllUserTokens(ctx, userID); err != nil {
+		return err
+	}
+	if err := s.revokeAccessTokensIssuedBefore(ctx, userID); err != nil {
+		return err
+	}
+	s.tokensRevoked(userID, ports.RevokeAllSessions)
+
+	return nil
+}
+
+// RevokeAccessToken implements AuthServicePort.RevokeAccessToken
+func (s *AuthService) RevokeAccessToken(ctx context.Context, accessToken string) (err error) {
+	ctx, span := startSpan(ctx, "AuthService.RevokeAccessToken")
+	defer func() { endSpan(span, err) }()
+
+	// Without a denylist access tokens simply run until they expire
+	if s.accessDenylist == nil {
+		return nil
+	}
+
+	claims, err := s.tokenProvider.ValidateToken(accessToken)
+	if errors.Is(err, domain.ErrTokenExpired) {
+		// Nothing left to revoke
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if claims.Type != domain.TokenTypeAccess {
+		return domain.ErrWrongTokenType
+	}
+
+	if err := s.accessDenylist.RevokeAccessToken(ctx, claims.ID, claims.Subject, claims.ExpiresAt); err != nil {
+		return err
+	}
+	s.tokensRevoked(claims.Subject, ports.RevokeAccessToken)
+
+	return nil
 }
 
 // GetUserByID implements AuthServicePort.GetUserByID
-func (s *AuthService) GetUserByID(ctx context.Context, userID string) (*domain.User, error) {
+func (s *AuthService) GetUserByID(ctx context.Context, userID string) (_ *domain.User, err error) {
+	ctx, span := startSpan(ctx, "AuthService.GetUserByID")
+	defer func() { endSpan(span, err) }()
+
 	return s.userRepo.GetUserByID(ctx, userID)
 }
 
 // GetUserByEmail implements AuthServicePort.GetUserByEmail
-func (s *AuthService) GetUserByEmail(ctx context.Context, email string) (*domain.User, error) {
+func (s *AuthService) GetUserByEmail(ctx context.Context, email string) (_ *domain.User, err error) {
+	ctx, span := startSpan(ctx, "AuthService.GetUserByEmail")
+	defer func() { endSpan(span, err) }()
+
 	return s.userRepo.GetUserByEmail(ctx, email)
 }
 
 // UpdateUserPassword implements AuthServicePort.UpdateUserPassword
-func (s *AuthService) UpdateUserPassword(ctx context.Context, userID, newPassword string) error {
+func (s *AuthService) UpdateUserPassword(ctx context.Context, userID, newPassword string) (err error) {
+	ctx, span := startSpan(ctx, "AuthService.UpdateUserPassword")
+	defer func() { endSpan(span, err) }()
+
+	user, err := s.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
 	// Validate password
-	if err := domain.ValidatePassword(newPassword); err != nil {
+	if err := s.passwordPolicy.Check(newPassword, user.Email); err != nil {
+		return err
+	}
+
+	if err := s.checkPasswordReuse(ctx, user, newPassword); err != nil {
 		return err
 	}
 
 	// Hash new password
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(newPassword), bcrypt.DefaultCost)
+	hashedPassword, err := s.hashPassword(ctx, newPassword)
 	if err != nil {
 		return err
 	}
 
-	// Update in repository
-	err = s.userRepo.UpdateUserPassword(ctx, userID, string(hashedPassword))
+	// Update the hash, remember the old one and revoke all existing tokens
+	// together, so a password change never leaves old sessions alive
+	err = s.uow.WithinTx(ctx, func(ctx context.Context, repos ports.TxRepositories) error {
+		if err := repos.Users.UpdateUserPassword(ctx, userID, hashedPassword); err != nil {
+			return err
+		}
+
+		if s.passwordHistorySize > 0 && repos.PasswordHistory != nil {
+			if err := repos.PasswordHistory.AddPasswordHistory(ctx, userID, user.PasswordHash, s.passwordHistorySize); err != nil {
+				return err
+			}
+		}
+
+		return repos.Tokens.RevokeAllUserTokens(ctx, userID)
+	})
 	if err != nil {
 		return err
 	}
-
-	// Revoke all existing tokens for security
-	if err := s.tokenRepo.RevokeAllUserTokens(ctx, userID); err != nil {
-		// Log but don't fail
+	if err := s.revokeAccessTokensIssuedBefore(ctx, userID); err != nil {
+		return err
 	}
+	s.tokensRevoked(userID, ports.RevokePasswordChange)
 
 	// Publish event
 	if s.eventPublisher != nil {
@@ -240,6 +414,308 @@ func (s *AuthService) UpdateUserPassword(ctx context.Context, userID, newPasswor
 	return nil
 }
 
+// checkPasswordReuse rejects the current password and, with a history
+// repository, the last passwordHistorySize previous ones
+func (s *AuthService) checkPasswordReuse(ctx context.Context, user *domain.User, newPassword string) error {
+	if s.passwordHistorySize <= 0 {
+		return nil
+	}
+
+	hashes := []string{user.PasswordHash}
+	if s.passwordHistory != nil {
+		previous, err := s.passwordHistory.GetPasswordHistory(ctx, user.ID, s.passwordHistorySize)
+		if err != nil {
+			return err
+		}
+		hashes = append(hashes, previous...)
+	}
+
+	for _, hash := range hashes {
+		if ok, err := s.verifyPassword(ctx, newPassword, hash); err == nil && ok {
+			return domain.NewPasswordPolicyError([]domain.PasswordViolation{{
+				Rule:    domain.PasswordRuleReused,
+				Message: "password was used recently",
+			}})
+		}
+	}
+
+	return nil
+}
+
+// loginResult classifies a Login outcome for metrics
+func loginResult(err error) string {
+	switch {
+	case err == nil:
+		return ports.LoginSucceeded
+	case errors.Is(err, domain.ErrInvalidCredentials):
+		return ports.LoginInvalidCredentials
+	case errors.Is(err, domain.ErrAccountLocked):
+		return ports.LoginLocked
+	default:
+		return ports.LoginError
+	}
+}
+
+// checkLockout returns an *domain.AccountLockedError while the login key is
+// locked out. The returned attempts are nil when lockout is disabled.
+func (s *AuthService) checkLockout(ctx context.Context, key string) (*domain.LoginAttempts, error) {
+	if s.loginAttempts == nil || !s.lockout.Enabled() {
+		return nil, nil
+	}
+
+	attempts, err := s.loginAttempts.GetLoginAttempts(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+
+	if attempts.IsLocked(time.Now()) {
+		return nil, &domain.AccountLockedError{Until: *attempts.LockedUntil}
+	}
+
+	return attempts, nil
+}
+
+// recordFailedLogin counts a failed login for the key and locks it once the
+// lockout policy says so. userID is empty for unknown emails, which lock out
+// the same way but report no event. It returns the error Login should
+// report.
+func (s *AuthService) recordFailedLogin(ctx context.Context, key, userID string) error {
+	if s.loginAttempts == nil || !s.lockout.Enabled() {
+		return domain.ErrInvalidCredentials
+	}
+
+	now := time.Now()
+	attempts, err := s.loginAttempts.RecordFailedLogin(ctx, key, now.Add(-s.lockout.ResetAfter))
+	if err != nil {
+		return err
+	}
+
+	duration := s.lockout.LockDuration(attempts.FailedCount)
+	if duration <= 0 {
+		return domain.ErrInvalidCredentials
+	}
+
+	until := now.Add(duration)
+	if err := s.loginAttempts.LockAccount(ctx, key, until); err != nil {
+		return err
+	}
+	s.metrics.AccountLocked()
+	logging.FromContext(ctx).Warn("account locked", "user_id", userID, "failed_attempts", attempts.FailedCount, "locked_until", until)
+
+	if s.eventPublisher != nil && userID != "" {
+		go s.eventPublisher.PublishAccountLocked(context.Background(), domain.AccountLockedEvent{
+			UserID:         userID,
+			FailedAttempts: attempts.FailedCount,
+			LockedUntil:    until,
+		})
+	}
+
+	return &domain.AccountLockedError{Until: until}
+}
+
+// hashPassword hashes in its own span, since hashing is deliberately slow
+func (s *AuthService) hashPassword(ctx context.Context, password string) (_ string, err error) {
+	_, span := startSpan(ctx, "PasswordHasher.Hash")
+	defer func() { endSpan(span, err) }()
+
+	return s.passwordHasher.Hash(password)
+}
+
+// dummyPasswordHash returns a hash of a random password, made once with the
+// configured hasher, to verify against when the email is unknown
+func (s *AuthService) dummyPasswordHash() string {
+	s.dummyHashOnce.Do(func() {
+		// On failure Verify fails fast on the empty hash; nothing else breaks
+		s.dummyHash, _ = s.passwordHasher.Hash(uuid.NewString())
+	})
+	return s.dummyHash
+}
+
+// verifyPassword verifies in its own span, since hashing is deliberately slow
+func (s *AuthService) verifyPassword(ctx context.Context, password, encodedHash string) (_ bool, err error) {
+	_, span := startSpan(ctx, "PasswordHasher.Verify")
+	defer func() { endSpan(span, err) }()
+
+	return s.passwordHasher.Verify(password, encodedHash)
+}
+
+// rehashPassword stores a fresh hash of a just-verified password. Failure
+// only delays the upgrade to the next login, so it does not fail the login.
+func (s *AuthService) rehashPassword(ctx context.Context, userID, password string) {
+	hashedPassword, err := s.hashPassword(ctx, password)
+	if err != nil {
+		logging.FromContext(ctx).Warn("failed to rehash password", "user_id", userID, "error", err)
+		return
+	}
+
+	if err := s.userRepo.UpdateUserPassword(ctx, userID, hashedPassword); err != nil {
+		logging.FromContext(ctx).Warn("failed to store rehashed password", "user_id", userID, "error", err)
+	}
+}
+
+// newRefreshTokenRecord builds the storage record for a newly issued refresh
+// token. The grant's session is the token family; a non-nil parent is the
+// token it replaces.
+func (s *AuthService) newRefreshTokenRecord(grant domain.TokenGrant, tokenPair *domain.TokenPair, parent *domain.RefreshToken) (*domain.RefreshToken, error) {
+	lookup, err := s.tokenProvider.ParseRefreshToken(tokenPair.RefreshToken)
+	if err != nil {
+		return nil, err
+	}
+
+	token := &domain.RefreshToken{
+		UserID:    grant.UserID,
+		TokenHash: lookup.TokenHash,
+		Selector:  lookup.Selector,
+		ExpiresAt: tokenPair.RefreshExpiresAt,
+		FamilyID:  grant.SessionID,
+	}
+
+	if parent != nil {
+		token.ParentID = parent.ID
+	}
+
+	return token, nil
+}
+
+// grantFor describes the tokens issued to a user for a session
+func (s *AuthService) grantFor(userID, sessionID string) domain.TokenGrant {
+	grant := domain.TokenGrant{
+		UserID:    userID,
+		SessionID: sessionID,
+		Scopes:    s.scopes,
+	}
+	if s.admins[userID] {
+		grant.Roles = []string{domain.RoleAdmin}
+	}
+	return grant
+}
+
+// validateAccessToken checks an access token, through the validation cache
+// when there is one, and against the denylist. Only successful validations
+// are cached, so an entry also records that the token was not revoked and a
+// hit touches neither the provider nor the denylist.
+func (s *AuthService) validateAccessToken(ctx context.Context, token string) (*domain.TokenClaims, error) {
+	var key string
+	var gen uint64
+	if s.validationCache != nil {
+		key = validationCacheKey(token)
+		// Read before validating, so a revocation racing this call keeps
+		// its token out of the cache
+		gen = s.validationCache.Generation()
+		if claims, ok := s.validationCache.Get(key); ok {
+			s.metrics.ValidationCacheLookup(true)
+			return claims, nil
+		}
+		s.metrics.ValidationCacheLookup(false)
+	}
+
+	claims, err := s.tokenProvider.ValidateToken(token)
+	if err != nil {
+		return nil, err
+	}
+
+	// Refresh tokens must never be accepted as access tokens
+	if claims.Type != domain.TokenTypeAccess {
+		return nil, domain.ErrWrongTokenType
+	}
+
+	if err := s.checkAccessDenylist(ctx, claims); err != nil {
+		return nil, err
+	}
+
+	if s.validationCache != nil {
+		s.validationCache.Add(key, claims, gen)
+	}
+
+	return claims, nil
+}
+
+// checkAccessDenylist returns ErrTokenRevoked for denied access tokens
+func (s *AuthService) checkAccessDenylist(ctx context.Context, claims *domain.TokenClaims) error {
+	if s.accessDenylist == nil {
+		return nil
+	}
+
+	// Fail closed: a token that cannot be checked is not accepted
+	revoked, err := s.accessDenylist.IsAccessTokenRevoked(ctx, claims.ID, claims.Subject, claims.IssuedAt)
+	if err != nil {
+		return err
+	}
+	if revoked {
+		return domain.ErrTokenRevoked
+	}
+	return nil
+}
+
+// tokensRevoked records a revocation and forgets the user's cached
+// validations so revoked sessions are not served from the cache
+func (s *AuthService) tokensRevoked(userID, reason string) {
+	s.metrics.TokensRevoked(reason)
+	if s.validationCache != nil {
+		s.validationCache.InvalidateUser(userID)
+	}
+}
+
+// revokeAccessTokensIssuedBefore denies every access token the user holds
+// now. Issue times are known to the microsecond, so the watermark is rounded
+// up to the next one; only a token issued later in that same microsecond
+// would be denied too, and a login right after this one validates.
+func (s *AuthService) revokeAccessTokensIssuedBefore(ctx context.Context, userID string) error {
+	if s.accessDenylist == nil {
+		return nil
+	}
+
+	before := time.Now().Truncate(time.Microsecond).Add(time.Microsecond)
+	return s.accessDenylist.RevokeAccessTokensIssuedBefore(ctx, userID, before, before.Add(s.accessExpiry))
+}
+
+// validationCacheKey hashes a token so the cache never holds usable tokens
+func validationCacheKey(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// handleRefreshTokenReuse revokes every token in the reused token's family
+// and reports the incident
+func (s *AuthService) handleRefreshTokenReuse(ctx context.Context, reused *domain.RefreshToken) error {
+	s.metrics.RefreshTokenReused()
+	logging.FromContext(ctx).Warn("refresh token reuse detected", "user_id", reused.UserID, "family_id", reused.FamilyID)
+
+	if err := s.tokenRepo.RevokeTokenFamily(ctx, reused.FamilyID); err != nil {
+		return err
+	}
+	// Access tokens carry no family, so every one the user holds goes
+	if err := s.revokeAccessTokensIssuedBefore(ctx, reused.UserID); err != nil {
+		return err
+	}
+	s.tokensRevoked(reused.UserID, ports.RevokeReuse)
+
+	if s.eventPublisher != nil {
+		go s.eventPublisher.PublishRefreshTokenReused(context.Background(), domain.RefreshTokenReusedEvent{
+			UserID:   reused.UserID,
+			FamilyID: reused.FamilyID,
+			TokenID:  reused.ID,
+		})
+	}
+
+	return domain.ErrRefreshTokenReused
+}
+
+// findRefreshToken verifies a presented refresh token with the provider and
+// loads its storage record, by selector for opaque tokens or by hash for JWTs
+func (s *AuthService) findRefreshToken(ctx context.Context, refreshToken string) (*domain.RefreshToken, error) {
+	lookup, err := s.tokenProvider.ParseRefreshToken(refreshToken)
+	if err != nil {
+		return nil, err
+	}
+
+	if lookup.Selector != "" {
+		return s.tokenRepo.GetRefreshTokenBySelector(ctx, lookup.Selector, lookup.TokenHash)
+	}
+
+	return s.tokenRepo.GetRefreshToken(ctx, lookup.TokenHash)
+}
+
 // convertJWTPairToPortsPair converts JWTTokenPair to ports.TokenPair
 func convertJWTPairToPortsPair(jwtPair *domain.TokenPair) *domain.TokenPair {
 	if jwtPair == nil {
@@ -0,0 +1,122 @@
+package core
+
+import (
+	"context"
+	"time"
+
+	"github.com/natrayanp/GoMicro/auth-service/internal/domain"
+	"github.com/natrayanp/GoMicro/auth-service/internal/ports"
+)
+
+// Option configures optional AuthService dependencies
+type Option func(*AuthService)
+
+// WithUnitOfWork makes multi-repository operations atomic. Without it they
+// run directly against the service's repositories.
+func WithUnitOfWork(uow ports.UnitOfWork) Option {
+	return func(s *AuthService) {
+		s.uow = uow
+	}
+}
+
+// WithPasswordHasher sets how passwords are hashed. The default is bcrypt
+// at bcrypt.DefaultCost.
+func WithPasswordHasher(hasher ports.PasswordHasher) Option {
+	return func(s *AuthService) {
+		s.passwordHasher = hasher
+	}
+}
+
+// WithPasswordPolicy sets the rules new passwords must satisfy. The default
+// is domain.DefaultPasswordPolicy.
+func WithPasswordPolicy(policy ports.PasswordPolicy) Option {
+	return func(s *AuthService) {
+		s.passwordPolicy = policy
+	}
+}
+
+// WithPasswordHistory rejects password changes that reuse the current
+// password or one of the last size previous ones
+func WithPasswordHistory(history ports.PasswordHistoryRepository, size int) Option {
+	return func(s *AuthService) {
+		s.passwordHistory = history
+		s.passwordHistorySize = size
+	}
+}
+
+// WithLockout tracks failed logins and temporarily locks accounts as the
+// policy describes. Without it failed logins are not limited.
+func WithLockout(attempts ports.LoginAttemptRepository, policy domain.LockoutPolicy) Option {
+	return func(s *AuthService) {
+		s.loginAttempts = attempts
+		s.lockout = policy
+	}
+}
+
+// WithMetrics reports logins, registrations and revocations. Without it
+// nothing is recorded.
+func WithMetrics(metrics ports.MetricsRecorder) Option {
+	return func(s *AuthService) {
+		s.metrics = metrics
+	}
+}
+
+// WithValidationCache caches successful access token validations, including
+// the denylist check, so cache hits skip the token store. Entries are
+// dropped whenever the service revokes a user's tokens, but only in this
+// instance's cache: other instances keep accepting a revoked token until
+// their entry expires, at most tokencache.MaxTTL later.
+func WithValidationCache(cache ports.ValidationCache) Option {
+	return func(s *AuthService) {
+		s.validationCache = cache
+	}
+}
+
+// WithAccessTokenDenylist lets access tokens be revoked before they expire,
+// singly or all of a user's at once. accessExpiry is the access token
+// lifetime, which bounds how long a user-wide revocation is kept. Without it
+// access tokens stay valid until they expire.
+func WithAccessTokenDenylist(denylist ports.AccessTokenDenylistRepository, accessExpiry time.Duration) Option {
+	return func(s *AuthService) {
+		s.accessDenylist = denylist
+		s.accessExpiry = accessExpiry
+	}
+}
+
+// WithAdmins grants RoleAdmin in the access tokens of the given users
+func WithAdmins(userIDs []string) Option {
+	return func(s *AuthService) {
+		s.admins = make(map[string]bool, len(userIDs))
+		for _, id := range userIDs {
+			s.admins[id] = true
+		}
+	}
+}
+
+// WithScopes grants the given scopes in every access token. Without it
+// tokens carry no scope claim.
+func WithScopes(scopes []string) Option {
+	return func(s *AuthService) {
+		s.scopes = scopes
+	}
+}
+
+// directUnitOfWork runs fn against the service's own repositories with no
+// transaction around them
+type directUnitOfWork struct {
+	repos ports.TxRepositories
+}
+
+func (u directUnitOfWork) WithinTx(ctx context.Context, fn func(ctx context.Context, repos ports.TxRepositories) error) error {
+	return fn(ctx, u.repos)
+}
+
+// noopMetrics discards everything
+type noopMetrics struct{}
+
+func (noopMetrics) UserRegistered()            {}
+func (noopMetrics) LoginAttempted(string)      {}
+func (noopMetrics) AccountLocked()             {}
+func (noopMetrics) RefreshTokenReused()        {}
+func (noopMetrics) TokensRevoked(string)       {}
+func (noopMetrics) ValidationCacheLookup(bool) {}
@@ -0,0 +1,38 @@
+package core
+
+import (
+	"context"
+	"errors"
+
+	"github.com/natrayanp/GoMicro/auth-service/internal/domain"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = otel.Tracer("github.com/natrayanp/GoMicro/auth-service/internal/core")
+
+// startSpan starts an internal span for a service step
+func startSpan(ctx context.Context, name string) (context.Context, trace.Span) {
+	return tracer.Start(ctx, name, trace.WithSpanKind(trace.SpanKindInternal))
+}
+
+// endSpan ends span, marking it failed for unexpected errors. Expected
+// outcomes such as wrong passwords are recorded without failing the span.
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		if !isExpectedError(err) {
+			span.SetStatus(codes.Error, err.Error())
+		}
+	}
+	span.End()
+}
+
+// isExpectedError reports whether err is a domain error, an outcome of the
+// request rather than a fault in the service
+func isExpectedError(err error) bool {
+	var domainErr *domain.Error
+	return errors.As(err, &domainErr)
+}
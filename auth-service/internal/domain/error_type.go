@@ -0,0 +1,70 @@
+package domain
+
+// Machine-readable reasons carried by domain errors. They are part of the
+// API: clients branch on them, so never change an existing value.
+const (
+	ReasonInvalidCredentials = "INVALID_CREDENTIALS"
+	ReasonUserExists         = "USER_EXISTS"
+	ReasonUserNotFound       = "USER_NOT_FOUND"
+	ReasonInvalidToken       = "INVALID_TOKEN"
+	ReasonTokenExpired       = "TOKEN_EXPIRED"
+	ReasonTokenRevoked       = "TOKEN_REVOKED"
+	ReasonWrongTokenType     = "WRONG_TOKEN_TYPE"
+	ReasonRefreshTokenReused = "REFRESH_TOKEN_REUSED"
+	ReasonInvalidEmail       = "INVALID_EMAIL"
+	ReasonPasswordTooShort   = "PASSWORD_TOO_SHORT"
+	ReasonPasswordPolicy     = "PASSWORD_POLICY"
+	ReasonAccountLocked      = "ACCOUNT_LOCKED"
+)
+
+// Error is a domain error with a stable reason and optional metadata.
+// The sentinel errors are *Error values; WithMetadata derives errors that
+// still match their sentinel with errors.Is.
+type Error struct {
+	Reason   string
+	Message  string
+	Metadata map[string]string
+
+	sentinel *Error
+}
+
+// NewError creates a sentinel domain error
+func NewError(reason, message string) *Error {
+	return &Error{Reason: reason, Message: message}
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// Unwrap returns the sentinel a derived error came from
+func (e *Error) Unwrap() error {
+	if e.sentinel == nil {
+		return nil
+	}
+	return e.sentinel
+}
+
+// WithMetadata returns a copy of e carrying extra key/value details, such
+// as when an expired token expired
+func (e *Error) WithMetadata(metadata map[string]string) *Error {
+	merged := make(map[string]string, len(e.Metadata)+len(metadata))
+	for k, v := range e.Metadata {
+		merged[k] = v
+	}
+	for k, v := range metadata {
+		merged[k] = v
+	}
+
+	sentinel := e.sentinel
+	if sentinel == nil {
+		sentinel = e
+	}
+
+	return &Error{
+		Reason:   e.Reason,
+		Message:  e.Message,
+		Metadata: merged,
+		sentinel: sentinel,
+	}
+}
@@ -1,14 +1,15 @@
 package domain
 
-import "errors"
-
 var (
-    ErrInvalidCredentials = errors.New("invalid credentials")
-    ErrUserExists         = errors.New("user already exists")
-    ErrUserNotFound       = errors.New("user not found")
-    ErrInvalidToken       = errors.New("invalid token")
-    ErrTokenExpired       = errors.New("token expired")
-    ErrInvalidEmail       = errors.New("invalid email address")
-    ErrPasswordTooShort   = errors.New("password must be at least 8 characters")
-    ErrTokenRevoked       = errors.New("token has been revoked")
+    ErrInvalidCredentials = NewError(ReasonInvalidCredentials, "invalid credentials")
+    ErrUserExists         = NewError(ReasonUserExists, "user already exists")
+    ErrUserNotFound       = NewError(ReasonUserNotFound, "user not found")
+    ErrInvalidToken       = NewError(ReasonInvalidToken, "invalid token")
+    ErrTokenExpired       = NewError(ReasonTokenExpired, "token expired")
+    ErrInvalidEmail       = NewError(ReasonInvalidEmail, "invalid email address")
+    ErrPasswordTooShort   = NewError(ReasonPasswordTooShort, "password must be at least 8 characters")
+    ErrTokenRevoked       = NewError(ReasonTokenRevoked, "token has been revoked")
+    ErrWrongTokenType     = NewError(ReasonWrongTokenType, "wrong token type")
+    ErrRefreshTokenReused = NewError(ReasonRefreshTokenReused, "refresh token reuse detected")
+    ErrAccountLocked      = NewError(ReasonAccountLocked, "account temporarily locked")
 )
\ No newline at end of file
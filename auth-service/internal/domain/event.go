@@ -1,6 +1,32 @@
 package domain
 
+import "time"
+
+const (
+	EventUserRegistered     = "user.registered"
+	EventUserLoggedIn       = "user.logged_in"
+	EventUserLoggedOut      = "user.logged_out"
+	EventPasswordChanged    = "user.password_changed"
+	EventRefreshTokenReused = "security.refresh_token_reused"
+	EventAccountLocked      = "security.account_locked"
+)
+
 type Event struct {
 	Name string
 	Data any
 }
+
+// RefreshTokenReusedEvent is published when a rotated refresh token is
+// presented again and its whole family has been revoked
+type RefreshTokenReusedEvent struct {
+	UserID   string
+	FamilyID string
+	TokenID  string
+}
+
+// AccountLockedEvent is published when repeated failed logins lock an account
+type AccountLockedEvent struct {
+	UserID         string
+	FailedAttempts int
+	LockedUntil    time.Time
+}
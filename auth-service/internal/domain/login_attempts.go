@@ -0,0 +1,88 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// LoginAttempts tracks consecutive failed logins for one login key
+type LoginAttempts struct {
+	Key          string // see LoginKey
+	FailedCount  int
+	LastFailedAt time.Time
+	LockedUntil  *time.Time
+}
+
+// LoginKey returns the key failed logins are tracked under: the email,
+// trimmed and lower-cased. Keying on the address rather than the user means
+// unknown addresses lock out exactly like registered ones.
+func LoginKey(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// IsLocked reports whether the account is locked at now
+func (a *LoginAttempts) IsLocked(now time.Time) bool {
+	return a.LockedUntil != nil && now.Before(*a.LockedUntil)
+}
+
+// AccountLockedError is returned while an account is locked out
+type AccountLockedError struct {
+	Until time.Time
+}
+
+func (e *AccountLockedError) Error() string {
+	return fmt.Sprintf("%s until %s", ErrAccountLocked, e.Until.UTC().Format(time.RFC3339))
+}
+
+// Unwrap returns ErrAccountLocked so errors.Is and errors.As reach the
+// sentinel and its reason
+func (e *AccountLockedError) Unwrap() error {
+	return ErrAccountLocked
+}
+
+// RetryAfter is how long the caller should wait, rounded up to a second
+func (e *AccountLockedError) RetryAfter() time.Duration {
+	wait := time.Until(e.Until)
+	if wait <= 0 {
+		return 0
+	}
+	return wait.Truncate(time.Second) + time.Second
+}
+
+// LockoutPolicy decides when repeated failed logins lock an account.
+// Once MaxAttempts consecutive failures are reached, every further failure
+// locks the account for BaseDuration doubled per extra failure, capped at
+// MaxDuration. The count starts over after ResetAfter without failures.
+type LockoutPolicy struct {
+	MaxAttempts  int // 0 disables lockout
+	BaseDuration time.Duration
+	MaxDuration  time.Duration
+	ResetAfter   time.Duration
+}
+
+// Enabled reports whether failed logins are tracked at all
+func (p LockoutPolicy) Enabled() bool {
+	return p.MaxAttempts > 0
+}
+
+// LockDuration returns how long to lock after failed consecutive failures,
+// or 0 if the account stays unlocked
+func (p LockoutPolicy) LockDuration(failed int) time.Duration {
+	if !p.Enabled() || failed < p.MaxAttempts {
+		return 0
+	}
+
+	duration := p.BaseDuration
+	for i := p.MaxAttempts; i < failed; i++ {
+		duration *= 2
+		if p.MaxDuration > 0 && duration >= p.MaxDuration {
+			return p.MaxDuration
+		}
+	}
+
+	if p.MaxDuration > 0 && duration > p.MaxDuration {
+		return p.MaxDuration
+	}
+	return duration
+}
@@ -0,0 +1,151 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// ErrPasswordPolicy matches every *PasswordPolicyError
+var ErrPasswordPolicy = NewError(ReasonPasswordPolicy, "password does not meet policy")
+
+// PasswordRule identifies one password policy rule
+type PasswordRule string
+
+const (
+	PasswordRuleMinLength     PasswordRule = "too_short"
+	PasswordRuleMaxLength     PasswordRule = "too_long"
+	PasswordRuleUppercase     PasswordRule = "missing_uppercase"
+	PasswordRuleLowercase     PasswordRule = "missing_lowercase"
+	PasswordRuleDigit         PasswordRule = "missing_digit"
+	PasswordRuleSymbol        PasswordRule = "missing_symbol"
+	PasswordRuleContainsEmail PasswordRule = "contains_email"
+	PasswordRuleCommon        PasswordRule = "common_password"
+	PasswordRuleReused        PasswordRule = "reused_password"
+)
+
+// PasswordViolation is a failed rule with a message safe to show users
+type PasswordViolation struct {
+	Rule    PasswordRule
+	Message string
+}
+
+// PasswordPolicyError lists every rule a password failed
+type PasswordPolicyError struct {
+	Violations []PasswordViolation
+}
+
+func (e *PasswordPolicyError) Error() string {
+	rules := make([]string, len(e.Violations))
+	for i, v := range e.Violations {
+		rules[i] = string(v.Rule)
+	}
+	return fmt.Sprintf("%s: %s", ErrPasswordPolicy, strings.Join(rules, ", "))
+}
+
+// Unwrap returns ErrPasswordPolicy so errors.As reaches its reason
+func (e *PasswordPolicyError) Unwrap() error {
+	return ErrPasswordPolicy
+}
+
+// Is matches ErrPasswordTooShort for a password that is too short so
+// existing checks keep working
+func (e *PasswordPolicyError) Is(target error) bool {
+	return target == ErrPasswordTooShort && e.Has(PasswordRuleMinLength)
+}
+
+// Has reports whether rule is among the violations
+func (e *PasswordPolicyError) Has(rule PasswordRule) bool {
+	for _, v := range e.Violations {
+		if v.Rule == rule {
+			return true
+		}
+	}
+	return false
+}
+
+// NewPasswordPolicyError returns nil when there are no violations
+func NewPasswordPolicyError(violations []PasswordViolation) error {
+	if len(violations) == 0 {
+		return nil
+	}
+	return &PasswordPolicyError{Violations: violations}
+}
+
+// PasswordPolicy holds the rules a new password must satisfy. Checks that
+// need outside data (common password lists, password history) build on it
+// outside the domain.
+type PasswordPolicy struct {
+	MinLength        int
+	MaxLength        int // 0 means no limit
+	MaxBytes         int // limit on the UTF-8 encoding, for hashes like bcrypt; 0 means no limit
+	RequireUppercase bool
+	RequireLowercase bool
+	RequireDigit     bool
+	RequireSymbol    bool
+	DisallowEmail    bool // reject passwords containing the email local-part
+}
+
+// DefaultPasswordPolicy matches ValidatePassword
+var DefaultPasswordPolicy = PasswordPolicy{MinLength: 8}
+
+// Check implements ports.PasswordPolicy
+func (p PasswordPolicy) Check(password, email string) error {
+	return NewPasswordPolicyError(p.Violations(password, email))
+}
+
+// Violations returns every rule password fails
+func (p PasswordPolicy) Violations(password, email string) []PasswordViolation {
+	var violations []PasswordViolation
+	add := func(rule PasswordRule, format string, args ...any) {
+		violations = append(violations, PasswordViolation{Rule: rule, Message: fmt.Sprintf(format, args...)})
+	}
+
+	length := utf8.RuneCountInString(password)
+	if length < p.MinLength {
+		add(PasswordRuleMinLength, "password must be at least %d characters", p.MinLength)
+	}
+	if p.MaxLength > 0 && length > p.MaxLength {
+		add(PasswordRuleMaxLength, "password must be at most %d characters", p.MaxLength)
+	} else if p.MaxBytes > 0 && len(password) > p.MaxBytes {
+		add(PasswordRuleMaxLength, "password must be at most %d bytes", p.MaxBytes)
+	}
+
+	var upper, lower, digit, symbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsDigit(r):
+			digit = true
+		case unicode.IsPunct(r) || unicode.IsSymbol(r) || unicode.IsSpace(r):
+			symbol = true
+		}
+	}
+
+	if p.RequireUppercase && !upper {
+		add(PasswordRuleUppercase, "password must contain an uppercase letter")
+	}
+	if p.RequireLowercase && !lower {
+		add(PasswordRuleLowercase, "password must contain a lowercase letter")
+	}
+	if p.RequireDigit && !digit {
+		add(PasswordRuleDigit, "password must contain a digit")
+	}
+	if p.RequireSymbol && !symbol {
+		add(PasswordRuleSymbol, "password must contain a symbol")
+	}
+
+	if p.DisallowEmail {
+		local, _, _ := strings.Cut(email, "@")
+		// Very short local-parts would reject too many unrelated passwords
+		if utf8.RuneCountInString(local) >= 3 && strings.Contains(strings.ToLower(password), strings.ToLower(local)) {
+			add(PasswordRuleContainsEmail, "password must not contain your email address")
+		}
+	}
+
+	return violations
+}
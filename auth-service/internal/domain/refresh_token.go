@@ -6,9 +6,13 @@ type RefreshToken struct {
     ID        string     `json:"id"`
     UserID    string     `json:"user_id"`
     TokenHash string     `json:"-"`
+    Selector  string     `json:"-"`                   // opaque tokens only
+    FamilyID  string     `json:"family_id"`           // shared by every rotation since login
+    ParentID  string     `json:"parent_id,omitempty"` // token this one was rotated from
     ExpiresAt time.Time  `json:"expires_at"`
     CreatedAt time.Time  `json:"created_at"`
     RevokedAt *time.Time `json:"revoked_at,omitempty"`
+    RotatedAt *time.Time `json:"rotated_at,omitempty"`
 }
 
 func (rt *RefreshToken) IsExpired() bool {
@@ -19,6 +23,19 @@ func (rt *RefreshToken) IsRevoked() bool {
     return rt.RevokedAt != nil
 }
 
+// IsRotated reports whether the token was already exchanged for a new one
+func (rt *RefreshToken) IsRotated() bool {
+    return rt.RotatedAt != nil
+}
+
 func (rt *RefreshToken) IsValid() bool {
     return !rt.IsExpired() && !rt.IsRevoked()
+}
+
+// RefreshTokenLookup identifies a presented refresh token in storage.
+// Opaque tokens are found by their non-secret Selector and then matched
+// on TokenHash; JWT refresh tokens are found by TokenHash alone.
+type RefreshTokenLookup struct {
+    Selector  string
+    TokenHash string
 }
\ No newline at end of file
@@ -0,0 +1,37 @@
+package domain
+
+import "time"
+
+// TokenType distinguishes access tokens from refresh tokens
+type TokenType string
+
+const (
+	TokenTypeAccess  TokenType = "access"
+	TokenTypeRefresh TokenType = "refresh"
+)
+
+// RoleAdmin is granted to users who may act on any account
+const RoleAdmin = "admin"
+
+// TokenGrant is what a newly issued token pair asserts about its holder
+type TokenGrant struct {
+	UserID    string
+	SessionID string // refresh token family, shared by every pair of one login
+	Roles     []string
+	Scopes    []string
+}
+
+// TokenClaims is the verified claim set of a token
+type TokenClaims struct {
+	ID        string // jti
+	Subject   string // user ID
+	Type      TokenType
+	SessionID string
+	Roles     []string
+	Scopes    []string
+	Issuer    string
+	Audience  []string
+	IssuedAt  time.Time
+	NotBefore time.Time
+	ExpiresAt time.Time
+}
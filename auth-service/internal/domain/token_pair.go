@@ -1,8 +1,11 @@
 package domain
 
+import "time"
+
 type TokenPair struct {
-	AccessToken  string
-	RefreshToken string
-	ExpiresIn    int64
-	TokenType    string
+	AccessToken      string
+	RefreshToken     string
+	ExpiresIn        int64
+	TokenType        string
+	RefreshExpiresAt time.Time
 }
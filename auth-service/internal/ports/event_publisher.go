@@ -11,4 +11,6 @@ type EventPublisherPort interface {
 	PublishUserLoggedIn(ctx context.Context, userID string) error
 	PublishUserLoggedOut(ctx context.Context, userID string) error
 	PublishPasswordChanged(ctx context.Context, userID string) error
+	PublishRefreshTokenReused(ctx context.Context, event domain.RefreshTokenReusedEvent) error
+	PublishAccountLocked(ctx context.Context, event domain.AccountLockedEvent) error
 }
@@ -0,0 +1,30 @@
+package ports
+
+// Login results reported to MetricsRecorder.LoginAttempted
+const (
+	LoginSucceeded          = "success"
+	LoginInvalidCredentials = "invalid_credentials"
+	LoginLocked             = "locked"
+	LoginError              = "error"
+)
+
+// Token revocation reasons reported to MetricsRecorder.TokensRevoked
+const (
+	RevokeLogout         = "logout"
+	RevokeAllSessions    = "all_sessions"
+	RevokePasswordChange = "password_change"
+	RevokeReuse          = "refresh_token_reuse"
+	RevokeAccessToken    = "access_token"
+)
+
+// MetricsRecorder counts domain events for monitoring
+type MetricsRecorder interface {
+	UserRegistered()
+	LoginAttempted(result string)
+	AccountLocked()
+	RefreshTokenReused()
+	// TokensRevoked counts one revocation, which may cover several tokens
+	TokensRevoked(reason string)
+	// ValidationCacheLookup counts a ValidationCache hit or miss
+	ValidationCacheLookup(hit bool)
+}
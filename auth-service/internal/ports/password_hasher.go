@@ -0,0 +1,16 @@
+package ports
+
+// PasswordHasher hashes and verifies user passwords. Encoded hashes are
+// self-describing (PHC string format), so a hasher can verify hashes made
+// with older algorithms or parameters and report that they need upgrading.
+type PasswordHasher interface {
+	Hash(password string) (string, error)
+	Verify(password, encodedHash string) (bool, error)
+	NeedsRehash(encodedHash string) bool
+}
+
+// PasswordPolicy checks a new password for the account with the given email.
+// Failures are *domain.PasswordPolicyError.
+type PasswordPolicy interface {
+	Check(password, email string) error
+}
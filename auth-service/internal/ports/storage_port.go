@@ -18,9 +18,52 @@ type UserRepository interface {
 
 // TokenRepository defines storage operations for tokens
 type TokenRepository interface {
-	CreateRefreshToken(ctx context.Context, userID, tokenHash string, expiresAt time.Time) error
+	CreateRefreshToken(ctx context.Context, token *domain.RefreshToken) error
 	GetRefreshToken(ctx context.Context, tokenHash string) (*domain.RefreshToken, error)
+	GetRefreshTokenBySelector(ctx context.Context, selector, tokenHash string) (*domain.RefreshToken, error)
 	RevokeRefreshToken(ctx context.Context, tokenHash string) error
+	RotateRefreshToken(ctx context.Context, oldTokenID string, next *domain.RefreshToken) error
+	RevokeTokenFamily(ctx context.Context, familyID string) error
 	RevokeAllUserTokens(ctx context.Context, userID string) error
 	GetValidRefreshTokens(ctx context.Context, userID string) ([]*domain.RefreshToken, error)
 }
+
+// PasswordHistoryRepository stores previous password hashes per user
+type PasswordHistoryRepository interface {
+	// AddPasswordHistory records a hash and keeps only the newest keep entries
+	AddPasswordHistory(ctx context.Context, userID, passwordHash string, keep int) error
+	// GetPasswordHistory returns up to limit hashes, newest first
+	GetPasswordHistory(ctx context.Context, userID string, limit int) ([]string, error)
+}
+
+// LoginAttemptRepository tracks failed logins per login key (see
+// domain.LoginKey) for account lockout
+type LoginAttemptRepository interface {
+	// GetLoginAttempts returns the key's record, or a zero record if the
+	// key has no failed logins
+	GetLoginAttempts(ctx context.Context, key string) (*domain.LoginAttempts, error)
+	// RecordFailedLogin atomically counts a failure and returns the new
+	// record. Failures before resetBefore no longer count.
+	RecordFailedLogin(ctx context.Context, key string, resetBefore time.Time) (*domain.LoginAttempts, error)
+	// LockAccount locks the key out until the given time
+	LockAccount(ctx context.Context, key string, until time.Time) error
+	// ResetLoginAttempts clears failures and any lock after a successful login
+	ResetLoginAttempts(ctx context.Context, key string) error
+}
+
+// AccessTokenDenylistRepository records access tokens revoked before they
+// expire. Entries are only needed until the tokens they cover expire.
+type AccessTokenDenylistRepository interface {
+	// RevokeAccessToken denies a single token by JWT ID until expiresAt
+	RevokeAccessToken(ctx context.Context, jti, userID string, expiresAt time.Time) error
+	// RevokeAccessTokensIssuedBefore denies every token the user was issued
+	// before the given time. The watermark only moves forward and is kept
+	// until expiresAt, when the last token it covers has expired.
+	RevokeAccessTokensIssuedBefore(ctx context.Context, userID string, before, expiresAt time.Time) error
+	// IsAccessTokenRevoked reports whether the token is denied by its JWT ID
+	// or by the user's watermark
+	IsAccessTokenRevoked(ctx context.Context, jti, userID string, issuedAt time.Time) (bool, error)
+	// DeleteExpiredRevocations removes entries whose tokens have all expired
+	// and returns how many were removed
+	DeleteExpiredRevocations(ctx context.Context) (int64, error)
+}
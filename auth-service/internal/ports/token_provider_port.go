@@ -7,7 +7,8 @@ import (
 type TokenProviderPort interface {
 	GenerateAccessToken(userID string) (string, error)
 	GenerateRefreshToken(userID string) (string, error)
-	GenerateTokenPair(userID string) (*domain.TokenPair, error)
-	ValidateToken(tokenString string) (string, string, error)
+	GenerateTokenPair(grant domain.TokenGrant) (*domain.TokenPair, error)
+	ValidateToken(tokenString string) (*domain.TokenClaims, error)
+	ParseRefreshToken(token string) (*domain.RefreshTokenLookup, error)
 	HashRefreshToken(token string) string
 }
@@ -0,0 +1,16 @@
+package ports
+
+import "context"
+
+// TxRepositories are repositories bound to a single unit of work
+type TxRepositories struct {
+	Users           UserRepository
+	Tokens          TokenRepository
+	PasswordHistory PasswordHistoryRepository
+}
+
+// UnitOfWork runs a group of repository operations atomically. Changes made
+// through repos are committed when fn returns nil and rolled back otherwise.
+type UnitOfWork interface {
+	WithinTx(ctx context.Context, fn func(ctx context.Context, repos TxRepositories) error) error
+}
@@ -0,0 +1,21 @@
+package ports
+
+import "github.com/natrayanp/GoMicro/auth-service/internal/domain"
+
+// ValidationCache remembers recently validated access tokens so repeated
+// Validate calls skip signature checks. Keys are token hashes, never the
+// tokens themselves.
+type ValidationCache interface {
+	// Get returns the cached claims, if present and not yet expired
+	Get(tokenHash string) (*domain.TokenClaims, bool)
+	// Generation returns a counter bumped by every InvalidateUser. Read it
+	// before validating a token and pass it to Add.
+	Generation() uint64
+	// Add caches claims until the token expires at the latest. It does
+	// nothing if users were invalidated since gen was read, as the claims
+	// may belong to a token revoked in the meantime.
+	Add(tokenHash string, claims *domain.TokenClaims, gen uint64)
+	// InvalidateUser drops every cached token of a user, for example after
+	// their tokens are revoked
+	InvalidateUser(userID string)
+}
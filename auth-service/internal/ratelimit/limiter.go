@@ -0,0 +1,146 @@
+// Package ratelimit enforces per-method token-bucket limits keyed by client
+// IP, login email or authenticated user.
+package ratelimit
+
+import (
+	"context"
+	"strings"
+	"time"
+)
+
+// Limit is a token bucket that holds up to Burst tokens and refills at Rate
+// tokens per second
+type Limit struct {
+	Rate  float64
+	Burst int
+}
+
+// Decision is the outcome of taking one token from a bucket
+type Decision struct {
+	Allowed    bool
+	Remaining  int
+	RetryAfter time.Duration // when the next token is available, if denied
+}
+
+// Store keeps token buckets. MemoryStore limits a single instance; a shared
+// store (Redis, ...) makes limits hold across replicas.
+type Store interface {
+	// Take removes one token from the bucket named key, creating it full
+	// if it does not exist yet
+	Take(ctx context.Context, key string, limit Limit) (Decision, error)
+
+	// Refund returns a token taken from the bucket named key, up to its
+	// burst
+	Refund(ctx context.Context, key string, limit Limit) error
+}
+
+// KeyKind selects what a rule's buckets are keyed by
+type KeyKind string
+
+const (
+	KeyIP        KeyKind = "ip"        // peer address
+	KeyEmail     KeyKind = "email"     // email field of the request, if any
+	KeyPrincipal KeyKind = "principal" // authenticated user, else peer address
+)
+
+// Rule limits one gRPC method, or every method when Method is "*"
+type Rule struct {
+	Method string // short method name such as "Login"
+	Key    KeyKind
+	Limit  Limit
+}
+
+// Keys identifies the caller of one request
+type Keys struct {
+	IP        string
+	Email     string
+	Principal string
+}
+
+// Limiter applies rules to requests using a Store
+type Limiter struct {
+	store Store
+	rules []Rule
+}
+
+// NewLimiter creates a limiter enforcing rules against store
+func NewLimiter(store Store, rules []Rule) *Limiter {
+	return &Limiter{store: store, rules: rules}
+}
+
+// Check takes a token from every bucket that applies to the request and
+// returns the first denial. A denied request costs nothing: tokens already
+// taken from earlier buckets are refunded. Rules keyed by email are skipped
+// for requests without one.
+func (l *Limiter) Check(ctx context.Context, method string, keys Keys) (Decision, error) {
+	allowed := Decision{Allowed: true, Remaining: -1}
+	var taken []takenToken
+
+	for _, rule := range l.rules {
+		if rule.Method != "*" && rule.Method != method {
+			continue
+		}
+
+		value, ok := rule.keyValue(keys)
+		if !ok {
+			continue
+		}
+
+		key := bucketKey(rule, value)
+		decision, err := l.store.Take(ctx, key, rule.Limit)
+		if err != nil {
+			l.refund(ctx, taken)
+			return Decision{}, err
+		}
+		if !decision.Allowed {
+			if err := l.refund(ctx, taken); err != nil {
+				return Decision{}, err
+			}
+			return decision, nil
+		}
+		taken = append(taken, takenToken{key: key, limit: rule.Limit})
+		if allowed.Remaining < 0 || decision.Remaining < allowed.Remaining {
+			allowed.Remaining = decision.Remaining
+		}
+	}
+
+	return allowed, nil
+}
+
+// takenToken records a token Check took, so it can be refunded
+type takenToken struct {
+	key   string
+	limit Limit
+}
+
+// refund returns the tokens of a request that was not let through
+func (l *Limiter) refund(ctx context.Context, taken []takenToken) error {
+	var firstErr error
+	for _, t := range taken {
+		if err := l.store.Refund(ctx, t.key, t.limit); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+func (r Rule) keyValue(keys Keys) (string, bool) {
+	switch r.Key {
+	case KeyEmail:
+		email := strings.ToLower(strings.TrimSpace(keys.Email))
+		return email, email != ""
+	case KeyPrincipal:
+		if keys.Principal != "" {
+			return "user:" + keys.Principal, true
+		}
+		return "ip:" + keys.IP, true
+	default:
+		return keys.IP, true
+	}
+}
+
+// bucketKey names the bucket of one rule and caller. Wildcard rules share a
+// bucket across methods.
+func bucketKey(rule Rule, value string) string {
+	return rule.Method + "|" + string(rule.Key) + "|" + value
+}
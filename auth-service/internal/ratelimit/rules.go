@@ -0,0 +1,94 @@
+package ratelimit
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/natrayanp/GoMicro/auth-service/internal/config"
+)
+
+// ParseRules parses a comma-separated list of Method=key:count/period
+// rules, for example "Login=ip:20/1m,Login=email:5/1m,*=ip:600/1m". Each
+// bucket holds count tokens and refills count tokens per period.
+func ParseRules(s string) ([]Rule, error) {
+	var rules []Rule
+
+	for _, item := range strings.Split(s, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+
+		rule, err := parseRule(item)
+		if err != nil {
+			return nil, fmt.Errorf("rate limit rule %q: %w", item, err)
+		}
+		rules = append(rules, rule)
+	}
+
+	return rules, nil
+}
+
+func parseRule(s string) (Rule, error) {
+	method, spec, ok := strings.Cut(s, "=")
+	if !ok || method == "" {
+		return Rule{}, fmt.Errorf("expected Method=key:count/period")
+	}
+
+	key, rate, ok := strings.Cut(spec, ":")
+	if !ok {
+		return Rule{}, fmt.Errorf("expected key:count/period")
+	}
+
+	kind := KeyKind(key)
+	switch kind {
+	case KeyIP, KeyEmail, KeyPrincipal:
+	default:
+		return Rule{}, fmt.Errorf("unknown key %q", key)
+	}
+
+	countStr, periodStr, ok := strings.Cut(rate, "/")
+	if !ok {
+		return Rule{}, fmt.Errorf("expected count/period")
+	}
+
+	count, err := strconv.Atoi(countStr)
+	if err != nil || count < 1 {
+		return Rule{}, fmt.Errorf("count must be a positive integer")
+	}
+
+	// Accept "s", "m" and "h" as shorthand for one of them
+	if periodStr != "" && !strings.ContainsAny(periodStr[:1], "0123456789") {
+		periodStr = "1" + periodStr
+	}
+	period, err := time.ParseDuration(periodStr)
+	if err != nil || period <= 0 {
+		return Rule{}, fmt.Errorf("invalid period %q", periodStr)
+	}
+
+	return Rule{
+		Method: method,
+		Key:    kind,
+		Limit: Limit{
+			Rate:  float64(count) / period.Seconds(),
+			Burst: count,
+		},
+	}, nil
+}
+
+// NewLimiterFromConfig creates a limiter for RATE_LIMIT_RULES, or returns
+// nil when rate limiting is disabled
+func NewLimiterFromConfig(cfg *config.RateLimitConfig, store Store) (*Limiter, error) {
+	if !cfg.Enabled {
+		return nil, nil
+	}
+
+	rules, err := ParseRules(cfg.Rules)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewLimiter(store, rules), nil
+}
@@ -0,0 +1,97 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/natrayanp/GoMicro/auth-service/internal/ports"
+)
+
+// AccessTokenDenylistRepository is a thread-safe in-memory
+// ports.AccessTokenDenylistRepository
+type AccessTokenDenylistRepository struct {
+	mu         sync.Mutex
+	tokens     map[string]revokedAccessToken   // jti → entry
+	watermarks map[string]accessTokenWatermark // user id → watermark
+}
+
+type revokedAccessToken struct {
+	userID    string
+	expiresAt time.Time
+}
+
+type accessTokenWatermark struct {
+	before    time.Time
+	expiresAt time.Time
+}
+
+var _ ports.AccessTokenDenylistRepository = (*AccessTokenDenylistRepository)(nil)
+
+func NewAccessTokenDenylistRepository() *AccessTokenDenylistRepository {
+	return &AccessTokenDenylistRepository{
+		tokens:     make(map[string]revokedAccessToken),
+		watermarks: make(map[string]accessTokenWatermark),
+	}
+}
+
+func (r *AccessTokenDenylistRepository) RevokeAccessToken(ctx context.Context, jti, userID string, expiresAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.tokens[jti]; !ok {
+		r.tokens[jti] = revokedAccessToken{userID: userID, expiresAt: expiresAt}
+	}
+
+	return nil
+}
+
+func (r *AccessTokenDenylistRepository) RevokeAccessTokensIssuedBefore(ctx context.Context, userID string, before, expiresAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	watermark := r.watermarks[userID]
+	if before.After(watermark.before) {
+		watermark.before = before
+	}
+	if expiresAt.After(watermark.expiresAt) {
+		watermark.expiresAt = expiresAt
+	}
+	r.watermarks[userID] = watermark
+
+	return nil
+}
+
+func (r *AccessTokenDenylistRepository) IsAccessTokenRevoked(ctx context.Context, jti, userID string, issuedAt time.Time) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if token, ok := r.tokens[jti]; ok && time.Now().Before(token.expiresAt) {
+		return true, nil
+	}
+
+	watermark, ok := r.watermarks[userID]
+	return ok && watermark.before.After(issuedAt), nil
+}
+
+func (r *AccessTokenDenylistRepository) DeleteExpiredRevocations(ctx context.Context) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	var deleted int64
+	for jti, token := range r.tokens {
+		if !now.Before(token.expiresAt) {
+			delete(r.tokens, jti)
+			deleted++
+		}
+	}
+	for userID, watermark := range r.watermarks {
+		if !now.Before(watermark.expiresAt) {
+			delete(r.watermarks, userID)
+			deleted++
+		}
+	}
+
+	return deleted, nil
+}
@@ -0,0 +1,86 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/natrayanp/GoMicro/auth-service/internal/domain"
+	"github.com/natrayanp/GoMicro/auth-service/internal/ports"
+)
+
+// LoginAttemptRepository is a thread-safe in-memory
+// ports.LoginAttemptRepository
+type LoginAttemptRepository struct {
+	mu       sync.Mutex
+	attempts map[string]*domain.LoginAttempts // login key → record
+}
+
+var _ ports.LoginAttemptRepository = (*LoginAttemptRepository)(nil)
+
+func NewLoginAttemptRepository() *LoginAttemptRepository {
+	return &LoginAttemptRepository{
+		attempts: make(map[string]*domain.LoginAttempts),
+	}
+}
+
+func (r *LoginAttemptRepository) GetLoginAttempts(ctx context.Context, key string) (*domain.LoginAttempts, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	attempts, ok := r.attempts[key]
+	if !ok {
+		return &domain.LoginAttempts{Key: key}, nil
+	}
+
+	return copyLoginAttempts(attempts), nil
+}
+
+func (r *LoginAttemptRepository) RecordFailedLogin(ctx context.Context, key string, resetBefore time.Time) (*domain.LoginAttempts, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	attempts, ok := r.attempts[key]
+	switch {
+	case !ok:
+		attempts = &domain.LoginAttempts{Key: key, FailedCount: 1}
+		r.attempts[key] = attempts
+	case attempts.LastFailedAt.Before(resetBefore):
+		attempts.FailedCount = 1
+	default:
+		attempts.FailedCount++
+	}
+	attempts.LastFailedAt = now
+
+	return copyLoginAttempts(attempts), nil
+}
+
+func (r *LoginAttemptRepository) LockAccount(ctx context.Context, key string, until time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if attempts, ok := r.attempts[key]; ok {
+		attempts.LockedUntil = &until
+	}
+
+	return nil
+}
+
+func (r *LoginAttemptRepository) ResetLoginAttempts(ctx context.Context, key string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.attempts, key)
+
+	return nil
+}
+
+func copyLoginAttempts(attempts *domain.LoginAttempts) *domain.LoginAttempts {
+	c := *attempts
+	if attempts.LockedUntil != nil {
+		lockedUntil := *attempts.LockedUntil
+		c.LockedUntil = &lockedUntil
+	}
+	return &c
+}
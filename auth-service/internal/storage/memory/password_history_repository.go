@@ -0,0 +1,68 @@
+package memory
+
+import (
+	"context"
+	"sync"
+
+	"github.com/natrayanp/GoMicro/auth-service/internal/ports"
+)
+
+// PasswordHistoryRepository is a thread-safe in-memory
+// ports.PasswordHistoryRepository
+type PasswordHistoryRepository struct {
+	mu      sync.RWMutex
+	history map[string][]string // user id → hashes, newest first
+}
+
+var _ ports.PasswordHistoryRepository = (*PasswordHistoryRepository)(nil)
+
+func NewPasswordHistoryRepository() *PasswordHistoryRepository {
+	return &PasswordHistoryRepository{
+		history: make(map[string][]string),
+	}
+}
+
+func (r *PasswordHistoryRepository) AddPasswordHistory(ctx context.Context, userID, passwordHash string, keep int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	previous, existed := r.history[userID]
+	hashes := append([]string{passwordHash}, previous...)
+	if len(hashes) > keep {
+		hashes = hashes[:keep]
+	}
+	r.history[userID] = hashes
+	if len(hashes) == 0 {
+		return nil
+	}
+
+	recordUndo(ctx, func() {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+
+		// Leave the history alone if another entry was added since; a
+		// later add stores a new slice
+		if current := r.history[userID]; len(current) == 0 || &current[0] != &hashes[0] {
+			return
+		}
+		if existed {
+			r.history[userID] = previous
+		} else {
+			delete(r.history, userID)
+		}
+	})
+
+	return nil
+}
+
+func (r *PasswordHistoryRepository) GetPasswordHistory(ctx context.Context, userID string, limit int) ([]string, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	hashes := r.history[userID]
+	if len(hashes) > limit {
+		hashes = hashes[:limit]
+	}
+
+	return append([]string{}, hashes...), nil
+}
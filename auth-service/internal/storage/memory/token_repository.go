@@ -0,0 +1,230 @@
+package memory
+
+import (
+	"context"
+	"crypto/subtle"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/natrayanp/GoMicro/auth-service/internal/domain"
+	"github.com/natrayanp/GoMicro/auth-service/internal/ports"
+)
+
+// TokenRepository is a thread-safe in-memory ports.TokenRepository
+type TokenRepository struct {
+	mu     sync.RWMutex
+	tokens map[string]*domain.RefreshToken // id → token
+}
+
+var _ ports.TokenRepository = (*TokenRepository)(nil)
+
+func NewTokenRepository() *TokenRepository {
+	return &TokenRepository{
+		tokens: make(map[string]*domain.RefreshToken),
+	}
+}
+
+// ------------------------------
+// CREATE
+// ------------------------------
+
+func (r *TokenRepository) CreateRefreshToken(ctx context.Context, token *domain.RefreshToken) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.insertLocked(ctx, token)
+	return nil
+}
+
+// ------------------------------
+// GET SINGLE
+// ------------------------------
+
+func (r *TokenRepository) GetRefreshToken(ctx context.Context, tokenHash string) (*domain.RefreshToken, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, token := range r.tokens {
+		if subtle.ConstantTimeCompare([]byte(token.TokenHash), []byte(tokenHash)) == 1 {
+			return copyToken(token), nil
+		}
+	}
+
+	return nil, domain.ErrInvalidToken
+}
+
+// ------------------------------
+// GET SINGLE BY SELECTOR
+// ------------------------------
+
+func (r *TokenRepository) GetRefreshTokenBySelector(ctx context.Context, selector, tokenHash string) (*domain.RefreshToken, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, token := range r.tokens {
+		if token.Selector == "" || token.Selector != selector {
+			continue
+		}
+
+		// The selector is not secret; the verifier hash decides
+		if subtle.ConstantTimeCompare([]byte(token.TokenHash), []byte(tokenHash)) != 1 {
+			return nil, domain.ErrInvalidToken
+		}
+		return copyToken(token), nil
+	}
+
+	return nil, domain.ErrInvalidToken
+}
+
+// ------------------------------
+// REVOKE SINGLE
+// ------------------------------
+
+func (r *TokenRepository) RevokeRefreshToken(ctx context.Context, tokenHash string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.revokeLocked(ctx, func(token *domain.RefreshToken) bool {
+		return token.TokenHash == tokenHash
+	})
+	return nil
+}
+
+// ------------------------------
+// ROTATE
+// ------------------------------
+
+// RotateRefreshToken revokes the old token and stores its successor under
+// one lock. Only a live token can be rotated; a second rotation of the same
+// token gets domain.ErrTokenRevoked.
+func (r *TokenRepository) RotateRefreshToken(ctx context.Context, oldTokenID string, next *domain.RefreshToken) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	old, ok := r.tokens[oldTokenID]
+	if !ok || old.RevokedAt != nil {
+		return domain.ErrTokenRevoked
+	}
+
+	now := time.Now()
+	old.RevokedAt = &now
+	old.RotatedAt = &now
+	recordUndo(ctx, func() {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+
+		if old.RotatedAt == &now {
+			old.RevokedAt = nil
+			old.RotatedAt = nil
+		}
+	})
+
+	r.insertLocked(ctx, next)
+	return nil
+}
+
+// ------------------------------
+// REVOKE FAMILY
+// ------------------------------
+
+func (r *TokenRepository) RevokeTokenFamily(ctx context.Context, familyID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.revokeLocked(ctx, func(token *domain.RefreshToken) bool {
+		return token.FamilyID == familyID
+	})
+	return nil
+}
+
+// ------------------------------
+// REVOKE ALL USER TOKENS
+// ------------------------------
+
+func (r *TokenRepository) RevokeAllUserTokens(ctx context.Context, userID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.revokeLocked(ctx, func(token *domain.RefreshToken) bool {
+		return token.UserID == userID
+	})
+	return nil
+}
+
+// ------------------------------
+// GET VALID TOKENS FOR USER
+// ------------------------------
+
+func (r *TokenRepository) GetValidRefreshTokens(ctx context.Context, userID string) ([]*domain.RefreshToken, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	tokens := []*domain.RefreshToken{}
+	for _, token := range r.tokens {
+		if token.UserID == userID && token.IsValid() {
+			tokens = append(tokens, copyToken(token))
+		}
+	}
+
+	return tokens, nil
+}
+
+// insertLocked stores a copy of token, filling ID, FamilyID and CreatedAt
+// the way the database defaults do
+func (r *TokenRepository) insertLocked(ctx context.Context, token *domain.RefreshToken) {
+	token.ID = uuid.NewString()
+	if token.FamilyID == "" {
+		token.FamilyID = uuid.NewString()
+	}
+	token.CreatedAt = time.Now()
+
+	r.tokens[token.ID] = copyToken(token)
+
+	id := token.ID
+	recordUndo(ctx, func() {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+
+		delete(r.tokens, id)
+	})
+}
+
+// revokeLocked revokes every live token that matches
+func (r *TokenRepository) revokeLocked(ctx context.Context, match func(*domain.RefreshToken) bool) {
+	now := time.Now()
+
+	var revoked []*domain.RefreshToken
+	for _, token := range r.tokens {
+		if match(token) && token.RevokedAt == nil {
+			token.RevokedAt = &now
+			revoked = append(revoked, token)
+		}
+	}
+
+	recordUndo(ctx, func() {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+
+		for _, token := range revoked {
+			if token.RevokedAt == &now {
+				token.RevokedAt = nil
+			}
+		}
+	})
+}
+
+// copyToken returns a deep copy so callers never share state with the store
+func copyToken(token *domain.RefreshToken) *domain.RefreshToken {
+	copied := *token
+	if token.RevokedAt != nil {
+		revokedAt := *token.RevokedAt
+		copied.RevokedAt = &revokedAt
+	}
+	if token.RotatedAt != nil {
+		rotatedAt := *token.RotatedAt
+		copied.RotatedAt = &rotatedAt
+	}
+	return &copied
+}
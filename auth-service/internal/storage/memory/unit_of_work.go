@@ -0,0 +1,70 @@
+package memory
+
+import (
+	"context"
+	"sync"
+
+	"github.com/natrayanp/GoMicro/auth-service/internal/ports"
+)
+
+// UnitOfWork is an in-memory ports.UnitOfWork for tests and local runs.
+// Units of work are serialized. Repository writes made with the unit of
+// work's context record how to undo themselves, and a failed unit of work
+// undoes them in reverse order; writes made outside it are left alone.
+type UnitOfWork struct {
+	mu    sync.Mutex
+	repos ports.TxRepositories
+}
+
+var _ ports.UnitOfWork = (*UnitOfWork)(nil)
+
+func NewUnitOfWork(repos ports.TxRepositories) *UnitOfWork {
+	return &UnitOfWork{repos: repos}
+}
+
+// WithinTx runs fn with the wrapped repositories
+func (u *UnitOfWork) WithinTx(ctx context.Context, fn func(ctx context.Context, repos ports.TxRepositories) error) error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	log := &undoLog{}
+	if err := fn(context.WithValue(ctx, undoLogKey{}, log), u.repos); err != nil {
+		log.rollback()
+		return err
+	}
+
+	return nil
+}
+
+// undoLog collects the inverse of every write made in one unit of work
+type undoLog struct {
+	mu    sync.Mutex
+	steps []func()
+}
+
+type undoLogKey struct{}
+
+// recordUndo registers undo with the unit of work running in ctx, if any.
+// Repositories call it while holding their lock; undo runs after they
+// released it and takes the lock itself.
+func recordUndo(ctx context.Context, undo func()) {
+	log, ok := ctx.Value(undoLogKey{}).(*undoLog)
+	if !ok {
+		return
+	}
+
+	log.mu.Lock()
+	defer log.mu.Unlock()
+
+	log.steps = append(log.steps, undo)
+}
+
+func (l *undoLog) rollback() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for i := len(l.steps) - 1; i >= 0; i-- {
+		l.steps[i]()
+	}
+	l.steps = nil
+}
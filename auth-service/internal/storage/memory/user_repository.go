@@ -0,0 +1,157 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/natrayanp/GoMicro/auth-service/internal/domain"
+	"github.com/natrayanp/GoMicro/auth-service/internal/ports"
+)
+
+// UserRepository is a thread-safe in-memory ports.UserRepository
+type UserRepository struct {
+	mu      sync.RWMutex
+	byID    map[string]*domain.User
+	byEmail map[string]string // email → id, case-sensitive like the users table
+}
+
+var _ ports.UserRepository = (*UserRepository)(nil)
+
+func NewUserRepository() *UserRepository {
+	return &UserRepository{
+		byID:    make(map[string]*domain.User),
+		byEmail: make(map[string]string),
+	}
+}
+
+// ------------------------------
+// CREATE USER
+// ------------------------------
+
+func (r *UserRepository) CreateUser(ctx context.Context, user *domain.User) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.byEmail[user.Email]; exists {
+		return domain.ErrUserExists
+	}
+
+	now := time.Now().UTC()
+	user.ID = uuid.NewString()
+	user.CreatedAt = now
+	user.UpdatedAt = now
+
+	stored := *user
+	r.byID[user.ID] = &stored
+	r.byEmail[user.Email] = user.ID
+
+	id, email := user.ID, user.Email
+	recordUndo(ctx, func() {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+
+		delete(r.byID, id)
+		if r.byEmail[email] == id {
+			delete(r.byEmail, email)
+		}
+	})
+
+	return nil
+}
+
+// ------------------------------
+// GET USER BY ID
+// ------------------------------
+
+func (r *UserRepository) GetUserByID(ctx context.Context, id string) (*domain.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	user, ok := r.byID[id]
+	if !ok {
+		return nil, domain.ErrUserNotFound
+	}
+
+	found := *user
+	return &found, nil
+}
+
+// ------------------------------
+// GET USER BY EMAIL
+// ------------------------------
+
+func (r *UserRepository) GetUserByEmail(ctx context.Context, email string) (*domain.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	id, ok := r.byEmail[email]
+	if !ok {
+		return nil, domain.ErrUserNotFound
+	}
+
+	found := *r.byID[id]
+	return &found, nil
+}
+
+// ------------------------------
+// UPDATE PASSWORD
+// ------------------------------
+
+func (r *UserRepository) UpdateUserPassword(ctx context.Context, userID, newPasswordHash string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	user, ok := r.byID[userID]
+	if !ok {
+		return domain.ErrUserNotFound
+	}
+
+	oldHash, oldUpdatedAt := user.PasswordHash, user.UpdatedAt
+	user.PasswordHash = newPasswordHash
+	user.UpdatedAt = time.Now().UTC()
+
+	recordUndo(ctx, func() {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+
+		// Leave the row alone if someone else changed it since
+		if user, ok := r.byID[userID]; ok && user.PasswordHash == newPasswordHash {
+			user.PasswordHash = oldHash
+			user.UpdatedAt = oldUpdatedAt
+		}
+	})
+
+	return nil
+}
+
+// ------------------------------
+// DELETE USER
+// ------------------------------
+
+func (r *UserRepository) DeleteUser(ctx context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	user, ok := r.byID[id]
+	if !ok {
+		return domain.ErrUserNotFound
+	}
+
+	delete(r.byEmail, user.Email)
+	delete(r.byID, id)
+
+	recordUndo(ctx, func() {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+
+		if _, taken := r.byEmail[user.Email]; !taken {
+			r.byID[id] = user
+			r.byEmail[user.Email] = id
+		}
+	})
+
+	return nil
+}
@@ -0,0 +1,85 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/natrayanp/GoMicro/auth-service/internal/ports"
+	"github.com/natrayanp/GoMicro/auth-service/internal/storage/postgres/sqlc"
+)
+
+type AccessTokenDenylistRepository struct {
+	queries *sqlc.Queries
+}
+
+func NewAccessTokenDenylistRepository(db *DB) ports.AccessTokenDenylistRepository {
+	return newAccessTokenDenylistRepository(db.Pool)
+}
+
+// newAccessTokenDenylistRepository builds a repository over the pool or an open transaction
+func newAccessTokenDenylistRepository(conn conn) *AccessTokenDenylistRepository {
+	return &AccessTokenDenylistRepository{
+		queries: sqlc.New(conn),
+	}
+}
+
+// ------------------------------
+// REVOKE
+// ------------------------------
+
+func (r *AccessTokenDenylistRepository) RevokeAccessToken(ctx context.Context, jti, userID string, expiresAt time.Time) error {
+	uid := pgtype.UUID{}
+	_ = uid.Scan(userID)
+
+	return r.queries.RevokeAccessToken(ctx, sqlc.RevokeAccessTokenParams{
+		Jti:       jti,
+		UserID:    uid,
+		ExpiresAt: pgtype.Timestamptz{Time: expiresAt, Valid: true},
+	})
+}
+
+func (r *AccessTokenDenylistRepository) RevokeAccessTokensIssuedBefore(ctx context.Context, userID string, before, expiresAt time.Time) error {
+	uid := pgtype.UUID{}
+	_ = uid.Scan(userID)
+
+	return r.queries.RevokeAccessTokensIssuedBefore(ctx, sqlc.RevokeAccessTokensIssuedBeforeParams{
+		UserID:        uid,
+		RevokedBefore: pgtype.Timestamptz{Time: before, Valid: true},
+		ExpiresAt:     pgtype.Timestamptz{Time: expiresAt, Valid: true},
+	})
+}
+
+// ------------------------------
+// CHECK
+// ------------------------------
+
+func (r *AccessTokenDenylistRepository) IsAccessTokenRevoked(ctx context.Context, jti, userID string, issuedAt time.Time) (bool, error) {
+	uid := pgtype.UUID{}
+	_ = uid.Scan(userID)
+
+	return r.queries.IsAccessTokenRevoked(ctx, sqlc.IsAccessTokenRevokedParams{
+		Jti:      jti,
+		UserID:   uid,
+		IssuedAt: pgtype.Timestamptz{Time: issuedAt, Valid: true},
+	})
+}
+
+// ------------------------------
+// PRUNE
+// ------------------------------
+
+func (r *AccessTokenDenylistRepository) DeleteExpiredRevocations(ctx context.Context) (int64, error) {
+	tokens, err := r.queries.DeleteExpiredRevokedAccessTokens(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	watermarks, err := r.queries.DeleteExpiredAccessTokenWatermarks(ctx)
+	if err != nil {
+		return tokens, err
+	}
+
+	return tokens + watermarks, nil
+}
@@ -3,7 +3,7 @@ package postgres
 import (
 	"context"
 	"fmt"
-	"log"
+	"log/slog"
 	"time"
 
 	"github.com/natrayanp/GoMicro/auth-service/internal/config"
@@ -29,6 +29,9 @@ func NewConnection(cfg *config.DatabaseConfig) (*DB, error) {
 		return nil, fmt.Errorf("failed to parse connection config: %w", err)
 	}
 
+	// Trace every query as a child of the calling request's span
+	poolConfig.ConnConfig.Tracer = queryTracer{}
+
 	pool, err := pgxpool.NewWithConfig(ctx, poolConfig)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create connection pool: %w", err)
@@ -39,7 +42,7 @@ func NewConnection(cfg *config.DatabaseConfig) (*DB, error) {
 		return nil, fmt.Errorf("failed to ping database: %w", err)
 	}
 
-	log.Println("Connected to PostgreSQL database")
+	slog.Info("connected to PostgreSQL database")
 	return &DB{Pool: pool}, nil
 }
 
@@ -0,0 +1,25 @@
+package postgres
+
+import (
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// uniqueViolation is the SQLSTATE for a unique constraint violation
+const uniqueViolation = "23505"
+
+// notFound maps a missing row to the given domain error and passes every
+// other error (connection failures, timeouts, ...) through unchanged
+func notFound(err, domainErr error) error {
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domainErr
+	}
+	return err
+}
+
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == uniqueViolation
+}
@@ -0,0 +1,98 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/natrayanp/GoMicro/auth-service/internal/domain"
+	"github.com/natrayanp/GoMicro/auth-service/internal/ports"
+	"github.com/natrayanp/GoMicro/auth-service/internal/storage/postgres/sqlc"
+)
+
+type LoginAttemptRepository struct {
+	queries *sqlc.Queries
+}
+
+func NewLoginAttemptRepository(db *DB) ports.LoginAttemptRepository {
+	return newLoginAttemptRepository(db.Pool)
+}
+
+// newLoginAttemptRepository builds a repository over the pool or an open transaction
+func newLoginAttemptRepository(conn conn) *LoginAttemptRepository {
+	return &LoginAttemptRepository{
+		queries: sqlc.New(conn),
+	}
+}
+
+// ------------------------------
+// GET
+// ------------------------------
+
+func (r *LoginAttemptRepository) GetLoginAttempts(ctx context.Context, key string) (*domain.LoginAttempts, error) {
+	result, err := r.queries.GetLoginAttempts(ctx, key)
+	if errors.Is(err, pgx.ErrNoRows) {
+		// No failures recorded yet
+		return &domain.LoginAttempts{Key: key}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return toDomainLoginAttempts(result), nil
+}
+
+// ------------------------------
+// RECORD FAILURE
+// ------------------------------
+
+func (r *LoginAttemptRepository) RecordFailedLogin(ctx context.Context, key string, resetBefore time.Time) (*domain.LoginAttempts, error) {
+	params := sqlc.RecordFailedLoginParams{
+		LoginKey:    key,
+		ResetBefore: pgtype.Timestamptz{Time: resetBefore, Valid: true},
+	}
+
+	result, err := r.queries.RecordFailedLogin(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	return toDomainLoginAttempts(result), nil
+}
+
+// ------------------------------
+// LOCK
+// ------------------------------
+
+func (r *LoginAttemptRepository) LockAccount(ctx context.Context, key string, until time.Time) error {
+	return r.queries.LockAccount(ctx, sqlc.LockAccountParams{
+		LoginKey:    key,
+		LockedUntil: pgtype.Timestamptz{Time: until, Valid: true},
+	})
+}
+
+// ------------------------------
+// RESET
+// ------------------------------
+
+func (r *LoginAttemptRepository) ResetLoginAttempts(ctx context.Context, key string) error {
+	return r.queries.ResetLoginAttempts(ctx, key)
+}
+
+// toDomainLoginAttempts converts a sqlc row → domain type
+func toDomainLoginAttempts(result sqlc.LoginAttempt) *domain.LoginAttempts {
+	var lockedUntil *time.Time
+	if result.LockedUntil.Valid {
+		lockedUntil = &result.LockedUntil.Time
+	}
+
+	return &domain.LoginAttempts{
+		Key:          result.LoginKey,
+		FailedCount:  int(result.FailedCount),
+		LastFailedAt: result.LastFailedAt.Time,
+		LockedUntil:  lockedUntil,
+	}
+}
@@ -0,0 +1,62 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/natrayanp/GoMicro/auth-service/internal/ports"
+	"github.com/natrayanp/GoMicro/auth-service/internal/storage/postgres/sqlc"
+)
+
+type PasswordHistoryRepository struct {
+	queries *sqlc.Queries
+}
+
+func NewPasswordHistoryRepository(db *DB) ports.PasswordHistoryRepository {
+	return newPasswordHistoryRepository(db.Pool)
+}
+
+// newPasswordHistoryRepository builds a repository over the pool or an open transaction
+func newPasswordHistoryRepository(conn conn) *PasswordHistoryRepository {
+	return &PasswordHistoryRepository{
+		queries: sqlc.New(conn),
+	}
+}
+
+// ------------------------------
+// ADD
+// ------------------------------
+
+func (r *PasswordHistoryRepository) AddPasswordHistory(ctx context.Context, userID, passwordHash string, keep int) error {
+	uid := pgtype.UUID{}
+	_ = uid.Scan(userID)
+
+	params := sqlc.AddPasswordHistoryParams{
+		UserID:       uid,
+		PasswordHash: passwordHash,
+	}
+
+	if err := r.queries.AddPasswordHistory(ctx, params); err != nil {
+		return err
+	}
+
+	return r.queries.PrunePasswordHistory(ctx, sqlc.PrunePasswordHistoryParams{
+		UserID: uid,
+		Limit:  int32(keep),
+	})
+}
+
+// ------------------------------
+// GET
+// ------------------------------
+
+func (r *PasswordHistoryRepository) GetPasswordHistory(ctx context.Context, userID string, limit int) ([]string, error) {
+	uid := pgtype.UUID{}
+	_ = uid.Scan(userID)
+
+	return r.queries.GetPasswordHistory(ctx, sqlc.GetPasswordHistoryParams{
+		UserID: uid,
+		Limit:  int32(limit),
+	})
+}
@@ -8,6 +8,26 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+type AccessTokenWatermark struct {
+	UserID        pgtype.UUID        `json:"user_id"`
+	RevokedBefore pgtype.Timestamptz `json:"revoked_before"`
+	ExpiresAt     pgtype.Timestamptz `json:"expires_at"`
+}
+
+type LoginAttempt struct {
+	LoginKey     string             `json:"login_key"`
+	FailedCount  int32              `json:"failed_count"`
+	LastFailedAt pgtype.Timestamptz `json:"last_failed_at"`
+	LockedUntil  pgtype.Timestamptz `json:"locked_until"`
+}
+
+type PasswordHistory struct {
+	ID           pgtype.UUID        `json:"id"`
+	UserID       pgtype.UUID        `json:"user_id"`
+	PasswordHash string             `json:"password_hash"`
+	CreatedAt    pgtype.Timestamptz `json:"created_at"`
+}
+
 type RefreshToken struct {
 	ID        pgtype.UUID        `json:"id"`
 	UserID    pgtype.UUID        `json:"user_id"`
@@ -15,6 +35,17 @@ type RefreshToken struct {
 	ExpiresAt pgtype.Timestamptz `json:"expires_at"`
 	CreatedAt pgtype.Timestamptz `json:"created_at"`
 	RevokedAt pgtype.Timestamptz `json:"revoked_at"`
+	Selector  pgtype.Text        `json:"selector"`
+	FamilyID  pgtype.UUID        `json:"family_id"`
+	ParentID  pgtype.UUID        `json:"parent_id"`
+	RotatedAt pgtype.Timestamptz `json:"rotated_at"`
+}
+
+type RevokedAccessToken struct {
+	Jti       string             `json:"jti"`
+	UserID    pgtype.UUID        `json:"user_id"`
+	ExpiresAt pgtype.Timestamptz `json:"expires_at"`
+	RevokedAt pgtype.Timestamptz `json:"revoked_at"`
 }
 
 type User struct {
@@ -11,16 +11,31 @@ import (
 )
 
 type Querier interface {
+	AddPasswordHistory(ctx context.Context, arg AddPasswordHistoryParams) error
 	CreateRefreshToken(ctx context.Context, arg CreateRefreshTokenParams) (RefreshToken, error)
 	CreateUser(ctx context.Context, arg CreateUserParams) (User, error)
-	DeleteUser(ctx context.Context, id pgtype.UUID) error
+	DeleteExpiredAccessTokenWatermarks(ctx context.Context) (int64, error)
+	DeleteExpiredRevokedAccessTokens(ctx context.Context) (int64, error)
+	DeleteUser(ctx context.Context, id pgtype.UUID) (int64, error)
+	GetLoginAttempts(ctx context.Context, loginKey string) (LoginAttempt, error)
+	GetPasswordHistory(ctx context.Context, arg GetPasswordHistoryParams) ([]string, error)
 	GetRefreshToken(ctx context.Context, tokenHash string) (RefreshToken, error)
+	GetRefreshTokenBySelector(ctx context.Context, selector pgtype.Text) (RefreshToken, error)
 	GetUserByEmail(ctx context.Context, email string) (User, error)
 	GetUserByID(ctx context.Context, id pgtype.UUID) (User, error)
 	GetValidRefreshTokens(ctx context.Context, userID pgtype.UUID) ([]RefreshToken, error)
+	IsAccessTokenRevoked(ctx context.Context, arg IsAccessTokenRevokedParams) (bool, error)
+	LockAccount(ctx context.Context, arg LockAccountParams) error
+	MarkRefreshTokenRotated(ctx context.Context, id pgtype.UUID) (int64, error)
+	PrunePasswordHistory(ctx context.Context, arg PrunePasswordHistoryParams) error
+	RecordFailedLogin(ctx context.Context, arg RecordFailedLoginParams) (LoginAttempt, error)
+	ResetLoginAttempts(ctx context.Context, loginKey string) error
+	RevokeAccessToken(ctx context.Context, arg RevokeAccessTokenParams) error
+	RevokeAccessTokensIssuedBefore(ctx context.Context, arg RevokeAccessTokensIssuedBeforeParams) error
 	RevokeAllUserTokens(ctx context.Context, userID pgtype.UUID) error
 	RevokeRefreshToken(ctx context.Context, tokenHash string) error
-	UpdateUserPassword(ctx context.Context, arg UpdateUserPasswordParams) error
+	RevokeTokenFamily(ctx context.Context, familyID pgtype.UUID) error
+	UpdateUserPassword(ctx context.Context, arg UpdateUserPasswordParams) (int64, error)
 }
 
 var _ Querier = (*Queries)(nil)
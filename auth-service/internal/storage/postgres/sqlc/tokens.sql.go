@@ -12,19 +12,29 @@ import (
 )
 
 const createRefreshToken = `-- name: CreateRefreshToken :one
-INSERT INTO refresh_tokens (user_id, token_hash, expires_at)
-VALUES ($1, $2, $3)
-RETURNING id, user_id, token_hash, expires_at, created_at, revoked_at
+INSERT INTO refresh_tokens (user_id, token_hash, expires_at, selector, family_id, parent_id)
+VALUES ($1, $2, $3, $4, COALESCE($5::uuid, gen_random_uuid()), $6)
+RETURNING id, user_id, token_hash, expires_at, created_at, revoked_at, selector, family_id, parent_id, rotated_at
 `
 
 type CreateRefreshTokenParams struct {
 	UserID    pgtype.UUID        `json:"user_id"`
 	TokenHash string             `json:"token_hash"`
 	ExpiresAt pgtype.Timestamptz `json:"expires_at"`
+	Selector  pgtype.Text        `json:"selector"`
+	FamilyID  pgtype.UUID        `json:"family_id"`
+	ParentID  pgtype.UUID        `json:"parent_id"`
 }
 
 func (q *Queries) CreateRefreshToken(ctx context.Context, arg CreateRefreshTokenParams) (RefreshToken, error) {
-	row := q.db.QueryRow(ctx, createRefreshToken, arg.UserID, arg.TokenHash, arg.ExpiresAt)
+	row := q.db.QueryRow(ctx, createRefreshToken,
+		arg.UserID,
+		arg.TokenHash,
+		arg.ExpiresAt,
+		arg.Selector,
+		arg.FamilyID,
+		arg.ParentID,
+	)
 	var i RefreshToken
 	err := row.Scan(
 		&i.ID,
@@ -33,12 +43,16 @@ func (q *Queries) CreateRefreshToken(ctx context.Context, arg CreateRefreshToken
 		&i.ExpiresAt,
 		&i.CreatedAt,
 		&i.RevokedAt,
+		&i.Selector,
+		&i.FamilyID,
+		&i.ParentID,
+		&i.RotatedAt,
 	)
 	return i, err
 }
 
 const getRefreshToken = `-- name: GetRefreshToken :one
-SELECT id, user_id, token_hash, expires_at, created_at, revoked_at
+SELECT id, user_id, token_hash, expires_at, created_at, revoked_at, selector, family_id, parent_id, rotated_at
 FROM refresh_tokens
 WHERE token_hash = $1
 `
@@ -53,12 +67,40 @@ func (q *Queries) GetRefreshToken(ctx context.Context, tokenHash string) (Refres
 		&i.ExpiresAt,
 		&i.CreatedAt,
 		&i.RevokedAt,
+		&i.Selector,
+		&i.FamilyID,
+		&i.ParentID,
+		&i.RotatedAt,
+	)
+	return i, err
+}
+
+const getRefreshTokenBySelector = `-- name: GetRefreshTokenBySelector :one
+SELECT id, user_id, token_hash, expires_at, created_at, revoked_at, selector, family_id, parent_id, rotated_at
+FROM refresh_tokens
+WHERE selector = $1
+`
+
+func (q *Queries) GetRefreshTokenBySelector(ctx context.Context, selector pgtype.Text) (RefreshToken, error) {
+	row := q.db.QueryRow(ctx, getRefreshTokenBySelector, selector)
+	var i RefreshToken
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.TokenHash,
+		&i.ExpiresAt,
+		&i.CreatedAt,
+		&i.RevokedAt,
+		&i.Selector,
+		&i.FamilyID,
+		&i.ParentID,
+		&i.RotatedAt,
 	)
 	return i, err
 }
 
 const getValidRefreshTokens = `-- name: GetValidRefreshTokens :many
-SELECT id, user_id, token_hash, expires_at, created_at, revoked_at
+SELECT id, user_id, token_hash, expires_at, created_at, revoked_at, selector, family_id, parent_id, rotated_at
 FROM refresh_tokens
 WHERE user_id = $1 
   AND revoked_at IS NULL 
@@ -81,6 +123,10 @@ func (q *Queries) GetValidRefreshTokens(ctx context.Context, userID pgtype.UUID)
 			&i.ExpiresAt,
 			&i.CreatedAt,
 			&i.RevokedAt,
+			&i.Selector,
+			&i.FamilyID,
+			&i.ParentID,
+			&i.RotatedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -92,6 +138,20 @@ func (q *Queries) GetValidRefreshTokens(ctx context.Context, userID pgtype.UUID)
 	return items, nil
 }
 
+const markRefreshTokenRotated = `-- name: MarkRefreshTokenRotated :execrows
+UPDATE refresh_tokens
+SET revoked_at = NOW(), rotated_at = NOW()
+WHERE id = $1 AND revoked_at IS NULL
+`
+
+func (q *Queries) MarkRefreshTokenRotated(ctx context.Context, id pgtype.UUID) (int64, error) {
+	result, err := q.db.Exec(ctx, markRefreshTokenRotated, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
 const revokeAllUserTokens = `-- name: RevokeAllUserTokens :exec
 UPDATE refresh_tokens
 SET revoked_at = NOW()
@@ -113,3 +173,14 @@ func (q *Queries) RevokeRefreshToken(ctx context.Context, tokenHash string) erro
 	_, err := q.db.Exec(ctx, revokeRefreshToken, tokenHash)
 	return err
 }
+
+const revokeTokenFamily = `-- name: RevokeTokenFamily :exec
+UPDATE refresh_tokens
+SET revoked_at = NOW()
+WHERE family_id = $1 AND revoked_at IS NULL
+`
+
+func (q *Queries) RevokeTokenFamily(ctx context.Context, familyID pgtype.UUID) error {
+	_, err := q.db.Exec(ctx, revokeTokenFamily, familyID)
+	return err
+}
@@ -2,6 +2,7 @@ package postgres
 
 import (
 	"context"
+	"crypto/subtle"
 	"time"
 
 	"github.com/jackc/pgx/v5/pgtype"
@@ -13,13 +14,18 @@ import (
 
 type TokenRepository struct {
 	queries *sqlc.Queries
-	db      *DB
+	conn    conn
 }
 
 func NewTokenRepository(db *DB) ports.TokenRepository {
+	return newTokenRepository(db.Pool)
+}
+
+// newTokenRepository builds a repository over the pool or an open transaction
+func newTokenRepository(conn conn) *TokenRepository {
 	return &TokenRepository{
-		queries: sqlc.New(db.Pool),
-		db:      db,
+		queries: sqlc.New(conn),
+		conn:    conn,
 	}
 }
 
@@ -27,25 +33,8 @@ func NewTokenRepository(db *DB) ports.TokenRepository {
 // CREATE
 // ------------------------------
 
-func (r *TokenRepository) CreateRefreshToken(ctx context.Context, userID, tokenHash string, expiresAt time.Time) error {
-	// Convert userID → pgtype.UUID
-	uid := pgtype.UUID{}
-	_ = uid.Scan(userID)
-
-	// Convert expiresAt → pgtype.Timestamptz
-	exp := pgtype.Timestamptz{
-		Time:  expiresAt,
-		Valid: true,
-	}
-
-	params := sqlc.CreateRefreshTokenParams{
-		UserID:    uid,
-		TokenHash: tokenHash,
-		ExpiresAt: exp,
-	}
-
-	_, err := r.queries.CreateRefreshToken(ctx, params)
-	return err
+func (r *TokenRepository) CreateRefreshToken(ctx context.Context, token *domain.RefreshToken) error {
+	return createRefreshToken(ctx, r.queries, token)
 }
 
 // ------------------------------
@@ -55,29 +44,33 @@ func (r *TokenRepository) CreateRefreshToken(ctx context.Context, userID, tokenH
 func (r *TokenRepository) GetRefreshToken(ctx context.Context, tokenHash string) (*domain.RefreshToken, error) {
 	result, err := r.queries.GetRefreshToken(ctx, tokenHash)
 	if err != nil {
+		return nil, notFound(err, domain.ErrInvalidToken)
+	}
+
+	// Re-check the stored hash in constant time before trusting the row
+	if subtle.ConstantTimeCompare([]byte(result.TokenHash), []byte(tokenHash)) != 1 {
 		return nil, domain.ErrInvalidToken
 	}
 
-	// Convert pgtype → domain types
-	id := result.ID.String()
-	userID := result.UserID.String()
+	return toDomainRefreshToken(result), nil
+}
 
-	expiresAt := result.ExpiresAt.Time
-	createdAt := result.CreatedAt.Time
+// ------------------------------
+// GET SINGLE BY SELECTOR
+// ------------------------------
 
-	var revokedAt *time.Time
-	if result.RevokedAt.Valid {
-		revokedAt = &result.RevokedAt.Time
+func (r *TokenRepository) GetRefreshTokenBySelector(ctx context.Context, selector, tokenHash string) (*domain.RefreshToken, error) {
+	result, err := r.queries.GetRefreshTokenBySelector(ctx, pgtype.Text{String: selector, Valid: true})
+	if err != nil {
+		return nil, notFound(err, domain.ErrInvalidToken)
 	}
 
-	return &domain.RefreshToken{
-		ID:        id,
-		UserID:    userID,
-		TokenHash: result.TokenHash,
-		ExpiresAt: expiresAt,
-		CreatedAt: createdAt,
-		RevokedAt: revokedAt,
-	}, nil
+	// The selector is not secret; the verifier hash decides
+	if subtle.ConstantTimeCompare([]byte(result.TokenHash), []byte(tokenHash)) != 1 {
+		return nil, domain.ErrInvalidToken
+	}
+
+	return toDomainRefreshToken(result), nil
 }
 
 // ------------------------------
@@ -88,6 +81,53 @@ func (r *TokenRepository) RevokeRefreshToken(ctx context.Context, tokenHash stri
 	return r.queries.RevokeRefreshToken(ctx, tokenHash)
 }
 
+// ------------------------------
+// ROTATE
+// ------------------------------
+
+// RotateRefreshToken revokes the old token and inserts its successor in one
+// transaction (a savepoint when already inside a unit of work). The revoke
+// only matches a row that is still live, so of two concurrent rotations of
+// the same token exactly one succeeds and the other gets
+// domain.ErrTokenRevoked.
+func (r *TokenRepository) RotateRefreshToken(ctx context.Context, oldTokenID string, next *domain.RefreshToken) error {
+	tx, err := r.conn.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	q := r.queries.WithTx(tx)
+
+	oid := pgtype.UUID{}
+	_ = oid.Scan(oldTokenID)
+
+	rows, err := q.MarkRefreshTokenRotated(ctx, oid)
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return domain.ErrTokenRevoked
+	}
+
+	if err := createRefreshToken(ctx, q, next); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// ------------------------------
+// REVOKE FAMILY
+// ------------------------------
+
+func (r *TokenRepository) RevokeTokenFamily(ctx context.Context, familyID string) error {
+	fid := pgtype.UUID{}
+	_ = fid.Scan(familyID)
+
+	return r.queries.RevokeTokenFamily(ctx, fid)
+}
+
 // ------------------------------
 // REVOKE ALL USER TOKENS
 // ------------------------------
@@ -114,26 +154,74 @@ func (r *TokenRepository) GetValidRefreshTokens(ctx context.Context, userID stri
 
 	tokens := make([]*domain.RefreshToken, len(results))
 	for i, result := range results {
-		id := result.ID.String()
-		u := result.UserID.String()
+		tokens[i] = toDomainRefreshToken(result)
+	}
 
-		expiresAt := result.ExpiresAt.Time
-		createdAt := result.CreatedAt.Time
+	return tokens, nil
+}
 
-		var revokedAt *time.Time
-		if result.RevokedAt.Valid {
-			revokedAt = &result.RevokedAt.Time
-		}
+// createRefreshToken inserts a token with the given queries, which may be
+// bound to a transaction
+func createRefreshToken(ctx context.Context, queries *sqlc.Queries, token *domain.RefreshToken) error {
+	// Convert userID → pgtype.UUID
+	uid := pgtype.UUID{}
+	_ = uid.Scan(token.UserID)
 
-		tokens[i] = &domain.RefreshToken{
-			ID:        id,
-			UserID:    u,
-			TokenHash: result.TokenHash,
-			ExpiresAt: expiresAt,
-			CreatedAt: createdAt,
-			RevokedAt: revokedAt,
-		}
+	// Convert expiresAt → pgtype.Timestamptz
+	exp := pgtype.Timestamptz{
+		Time:  token.ExpiresAt,
+		Valid: true,
 	}
 
-	return tokens, nil
+	// An empty FamilyID starts a new family; ParentID stays NULL for logins
+	familyID := pgtype.UUID{}
+	_ = familyID.Scan(token.FamilyID)
+	parentID := pgtype.UUID{}
+	_ = parentID.Scan(token.ParentID)
+
+	params := sqlc.CreateRefreshTokenParams{
+		UserID:    uid,
+		TokenHash: token.TokenHash,
+		ExpiresAt: exp,
+		Selector:  pgtype.Text{String: token.Selector, Valid: token.Selector != ""},
+		FamilyID:  familyID,
+		ParentID:  parentID,
+	}
+
+	result, err := queries.CreateRefreshToken(ctx, params)
+	if err != nil {
+		return err
+	}
+
+	token.ID = result.ID.String()
+	token.FamilyID = result.FamilyID.String()
+	token.CreatedAt = result.CreatedAt.Time
+
+	return nil
+}
+
+// toDomainRefreshToken converts a sqlc row → domain type
+func toDomainRefreshToken(result sqlc.RefreshToken) *domain.RefreshToken {
+	var revokedAt *time.Time
+	if result.RevokedAt.Valid {
+		revokedAt = &result.RevokedAt.Time
+	}
+
+	var rotatedAt *time.Time
+	if result.RotatedAt.Valid {
+		rotatedAt = &result.RotatedAt.Time
+	}
+
+	return &domain.RefreshToken{
+		ID:        result.ID.String(),
+		UserID:    result.UserID.String(),
+		TokenHash: result.TokenHash,
+		Selector:  result.Selector.String,
+		FamilyID:  result.FamilyID.String(),
+		ParentID:  result.ParentID.String(),
+		ExpiresAt: result.ExpiresAt.Time,
+		CreatedAt: result.CreatedAt.Time,
+		RevokedAt: revokedAt,
+		RotatedAt: rotatedAt,
+	}
 }
@@ -0,0 +1,47 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/natrayanp/GoMicro/auth-service/internal/ports"
+	"github.com/natrayanp/GoMicro/auth-service/internal/storage/postgres/sqlc"
+)
+
+// conn is satisfied by both *pgxpool.Pool and pgx.Tx, so repositories run
+// the same way on the pool and inside a transaction. Begin on a pgx.Tx
+// opens a savepoint.
+type conn interface {
+	sqlc.DBTX
+	Begin(ctx context.Context) (pgx.Tx, error)
+}
+
+type UnitOfWork struct {
+	db *DB
+}
+
+func NewUnitOfWork(db *DB) ports.UnitOfWork {
+	return &UnitOfWork{db: db}
+}
+
+// WithinTx runs fn in a single database transaction
+func (u *UnitOfWork) WithinTx(ctx context.Context, fn func(ctx context.Context, repos ports.TxRepositories) error) error {
+	tx, err := u.db.Pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	repos := ports.TxRepositories{
+		Users:           newUserRepository(tx),
+		Tokens:          newTokenRepository(tx),
+		PasswordHistory: newPasswordHistoryRepository(tx),
+	}
+
+	if err := fn(ctx, repos); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
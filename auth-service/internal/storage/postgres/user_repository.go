@@ -12,13 +12,18 @@ import (
 
 type UserRepository struct {
 	queries *sqlc.Queries
-	db      *DB
+	conn    conn
 }
 
 func NewUserRepository(db *DB) ports.UserRepository {
+	return newUserRepository(db.Pool)
+}
+
+// newUserRepository builds a repository over the pool or an open transaction
+func newUserRepository(conn conn) *UserRepository {
 	return &UserRepository{
-		queries: sqlc.New(db.Pool),
-		db:      db,
+		queries: sqlc.New(conn),
+		conn:    conn,
 	}
 }
 
@@ -34,6 +39,9 @@ func (r *UserRepository) CreateUser(ctx context.Context, user *domain.User) erro
 
 	result, err := r.queries.CreateUser(ctx, params)
 	if err != nil {
+		if isUniqueViolation(err) {
+			return domain.ErrUserExists
+		}
 		return err
 	}
 
@@ -56,7 +64,7 @@ func (r *UserRepository) GetUserByID(ctx context.Context, id string) (*domain.Us
 
 	result, err := r.queries.GetUserByID(ctx, uid)
 	if err != nil {
-		return nil, domain.ErrUserNotFound
+		return nil, notFound(err, domain.ErrUserNotFound)
 	}
 
 	return &domain.User{
@@ -75,7 +83,7 @@ func (r *UserRepository) GetUserByID(ctx context.Context, id string) (*domain.Us
 func (r *UserRepository) GetUserByEmail(ctx context.Context, email string) (*domain.User, error) {
 	result, err := r.queries.GetUserByEmail(ctx, email)
 	if err != nil {
-		return nil, domain.ErrUserNotFound
+		return nil, notFound(err, domain.ErrUserNotFound)
 	}
 
 	return &domain.User{
@@ -100,7 +108,15 @@ func (r *UserRepository) UpdateUserPassword(ctx context.Context, userID, newPass
 		PasswordHash: newPasswordHash,
 	}
 
-	return r.queries.UpdateUserPassword(ctx, params)
+	rows, err := r.queries.UpdateUserPassword(ctx, params)
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return domain.ErrUserNotFound
+	}
+
+	return nil
 }
 
 // ------------------------------
@@ -111,5 +127,13 @@ func (r *UserRepository) DeleteUser(ctx context.Context, id string) error {
 	uid := pgtype.UUID{}
 	_ = uid.Scan(id)
 
-	return r.queries.DeleteUser(ctx, uid)
+	rows, err := r.queries.DeleteUser(ctx, uid)
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return domain.ErrUserNotFound
+	}
+
+	return nil
 }
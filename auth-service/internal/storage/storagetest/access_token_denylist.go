@@ -0,0 +1,95 @@
+package storagetest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/natrayanp/GoMicro/auth-service/internal/ports"
+)
+
+// RunAccessTokenDenylistRepositoryTests runs the access token denylist
+// contract. newRepos must return repositories over empty, shared storage.
+func RunAccessTokenDenylistRepositoryTests(t *testing.T, newRepos func(t *testing.T) (ports.UserRepository, ports.AccessTokenDenylistRepository)) {
+	t.Run("RevokeByJTI", func(t *testing.T) {
+		users, denylist := newRepos(t)
+		ctx := context.Background()
+		user := newUser(t, users, "alice@example.com")
+		issuedAt := time.Now().Add(-time.Minute)
+
+		revoked, err := denylist.IsAccessTokenRevoked(ctx, "jti-1", user.ID, issuedAt)
+		require.NoError(t, err)
+		assert.False(t, revoked)
+
+		require.NoError(t, denylist.RevokeAccessToken(ctx, "jti-1", user.ID, time.Now().Add(time.Hour)))
+		// Revoking twice is not an error
+		require.NoError(t, denylist.RevokeAccessToken(ctx, "jti-1", user.ID, time.Now().Add(time.Hour)))
+
+		revoked, err = denylist.IsAccessTokenRevoked(ctx, "jti-1", user.ID, issuedAt)
+		require.NoError(t, err)
+		assert.True(t, revoked)
+
+		revoked, err = denylist.IsAccessTokenRevoked(ctx, "jti-2", user.ID, issuedAt)
+		require.NoError(t, err)
+		assert.False(t, revoked, "other tokens of the user stay valid")
+	})
+
+	t.Run("WatermarkCoversEarlierTokens", func(t *testing.T) {
+		users, denylist := newRepos(t)
+		ctx := context.Background()
+		alice := newUser(t, users, "alice@example.com")
+		bob := newUser(t, users, "bob@example.com")
+		now := time.Now().Truncate(time.Second)
+
+		require.NoError(t, denylist.RevokeAccessTokensIssuedBefore(ctx, alice.ID, now, now.Add(time.Hour)))
+
+		revoked, err := denylist.IsAccessTokenRevoked(ctx, "old", alice.ID, now.Add(-time.Second))
+		require.NoError(t, err)
+		assert.True(t, revoked)
+
+		revoked, err = denylist.IsAccessTokenRevoked(ctx, "new", alice.ID, now)
+		require.NoError(t, err)
+		assert.False(t, revoked, "tokens issued at the watermark stay valid")
+
+		revoked, err = denylist.IsAccessTokenRevoked(ctx, "other", bob.ID, now.Add(-time.Second))
+		require.NoError(t, err)
+		assert.False(t, revoked)
+
+		// An older watermark never moves it back
+		require.NoError(t, denylist.RevokeAccessTokensIssuedBefore(ctx, alice.ID, now.Add(-time.Hour), now.Add(time.Minute)))
+		revoked, err = denylist.IsAccessTokenRevoked(ctx, "old", alice.ID, now.Add(-time.Second))
+		require.NoError(t, err)
+		assert.True(t, revoked)
+	})
+
+	t.Run("DeleteExpiredRevocations", func(t *testing.T) {
+		users, denylist := newRepos(t)
+		ctx := context.Background()
+		user := newUser(t, users, "alice@example.com")
+		now := time.Now()
+
+		require.NoError(t, denylist.RevokeAccessToken(ctx, "expired", user.ID, now.Add(-time.Minute)))
+		require.NoError(t, denylist.RevokeAccessToken(ctx, "live", user.ID, now.Add(time.Hour)))
+		require.NoError(t, denylist.RevokeAccessTokensIssuedBefore(ctx, user.ID, now.Add(-2*time.Minute), now.Add(-time.Minute)))
+
+		// An expired entry no longer denies anything, even before it is pruned
+		revoked, err := denylist.IsAccessTokenRevoked(ctx, "expired", user.ID, now)
+		require.NoError(t, err)
+		assert.False(t, revoked)
+
+		deleted, err := denylist.DeleteExpiredRevocations(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), deleted)
+
+		revoked, err = denylist.IsAccessTokenRevoked(ctx, "live", user.ID, now)
+		require.NoError(t, err)
+		assert.True(t, revoked)
+
+		deleted, err = denylist.DeleteExpiredRevocations(ctx)
+		require.NoError(t, err)
+		assert.Zero(t, deleted)
+	})
+}
@@ -0,0 +1,95 @@
+package storagetest
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/natrayanp/GoMicro/auth-service/internal/domain"
+	"github.com/natrayanp/GoMicro/auth-service/internal/ports"
+)
+
+// RunLoginAttemptRepositoryTests runs the login attempt contract.
+// newRepo must return a repository over empty storage.
+func RunLoginAttemptRepositoryTests(t *testing.T, newRepo func(t *testing.T) ports.LoginAttemptRepository) {
+	t.Run("ZeroRecordForUnknownKey", func(t *testing.T) {
+		attempts := newRepo(t)
+		key := domain.LoginKey("Alice@example.com")
+
+		record, err := attempts.GetLoginAttempts(context.Background(), key)
+		require.NoError(t, err)
+		assert.Equal(t, key, record.Key)
+		assert.Zero(t, record.FailedCount)
+		assert.Nil(t, record.LockedUntil)
+	})
+
+	t.Run("RecordLockAndReset", func(t *testing.T) {
+		attempts := newRepo(t)
+		ctx := context.Background()
+		key := domain.LoginKey("Alice@example.com")
+		resetBefore := time.Now().Add(-time.Hour)
+
+		for i := 1; i <= 3; i++ {
+			record, err := attempts.RecordFailedLogin(ctx, key, resetBefore)
+			require.NoError(t, err)
+			assert.Equal(t, i, record.FailedCount)
+		}
+
+		until := time.Now().Add(time.Minute).Truncate(time.Microsecond)
+		require.NoError(t, attempts.LockAccount(ctx, key, until))
+
+		record, err := attempts.GetLoginAttempts(ctx, key)
+		require.NoError(t, err)
+		assert.Equal(t, 3, record.FailedCount)
+		require.NotNil(t, record.LockedUntil)
+		assert.WithinDuration(t, until, *record.LockedUntil, time.Millisecond)
+		assert.True(t, record.IsLocked(time.Now()))
+
+		require.NoError(t, attempts.ResetLoginAttempts(ctx, key))
+
+		record, err = attempts.GetLoginAttempts(ctx, key)
+		require.NoError(t, err)
+		assert.Zero(t, record.FailedCount)
+		assert.Nil(t, record.LockedUntil)
+	})
+
+	t.Run("StaleFailuresStartOver", func(t *testing.T) {
+		attempts := newRepo(t)
+		ctx := context.Background()
+		key := domain.LoginKey("Alice@example.com")
+
+		_, err := attempts.RecordFailedLogin(ctx, key, time.Now().Add(-time.Hour))
+		require.NoError(t, err)
+
+		// Every earlier failure is older than a reset point in the future
+		record, err := attempts.RecordFailedLogin(ctx, key, time.Now().Add(time.Hour))
+		require.NoError(t, err)
+		assert.Equal(t, 1, record.FailedCount)
+	})
+
+	t.Run("ConcurrentFailuresAllCount", func(t *testing.T) {
+		attempts := newRepo(t)
+		key := domain.LoginKey("Alice@example.com")
+		resetBefore := time.Now().Add(-time.Hour)
+
+		const n = 10
+		var wg sync.WaitGroup
+		for i := 0; i < n; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, err := attempts.RecordFailedLogin(context.Background(), key, resetBefore)
+				assert.NoError(t, err)
+			}()
+		}
+		wg.Wait()
+
+		record, err := attempts.GetLoginAttempts(context.Background(), key)
+		require.NoError(t, err)
+		assert.Equal(t, n, record.FailedCount)
+	})
+}
@@ -0,0 +1,53 @@
+package storagetest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/natrayanp/GoMicro/auth-service/internal/ports"
+)
+
+// RunPasswordHistoryRepositoryTests runs the password history contract.
+// newRepos must return repositories over empty, shared storage.
+func RunPasswordHistoryRepositoryTests(t *testing.T, newRepos func(t *testing.T) (ports.UserRepository, ports.PasswordHistoryRepository)) {
+	t.Run("NewestFirstAndPruned", func(t *testing.T) {
+		users, history := newRepos(t)
+		ctx := context.Background()
+
+		user := newUser(t, users, "alice@example.com")
+
+		for _, hash := range []string{"hash-1", "hash-2", "hash-3"} {
+			require.NoError(t, history.AddPasswordHistory(ctx, user.ID, hash, 2))
+		}
+
+		hashes, err := history.GetPasswordHistory(ctx, user.ID, 10)
+		require.NoError(t, err)
+		assert.Len(t, hashes, 2)
+		assert.ElementsMatch(t, []string{"hash-2", "hash-3"}, hashes)
+
+		hashes, err = history.GetPasswordHistory(ctx, user.ID, 1)
+		require.NoError(t, err)
+		assert.Len(t, hashes, 1)
+	})
+
+	t.Run("PerUser", func(t *testing.T) {
+		users, history := newRepos(t)
+		ctx := context.Background()
+
+		alice := newUser(t, users, "alice@example.com")
+		bob := newUser(t, users, "bob@example.com")
+		require.NoError(t, history.AddPasswordHistory(ctx, alice.ID, "alice-hash", 5))
+
+		hashes, err := history.GetPasswordHistory(ctx, bob.ID, 5)
+		require.NoError(t, err)
+		assert.Empty(t, hashes)
+
+		hashes, err = history.GetPasswordHistory(ctx, uuid.NewString(), 5)
+		require.NoError(t, err)
+		assert.Empty(t, hashes)
+	})
+}
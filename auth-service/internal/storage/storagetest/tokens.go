@@ -0,0 +1,231 @@
+package storagetest
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/natrayanp/GoMicro/auth-service/internal/domain"
+	"github.com/natrayanp/GoMicro/auth-service/internal/ports"
+)
+
+// RunTokenRepositoryTests runs the token repository contract. newRepos must
+// return repositories over empty, shared storage for every call; users are
+// needed because tokens belong to one.
+func RunTokenRepositoryTests(t *testing.T, newRepos func(t *testing.T) (ports.UserRepository, ports.TokenRepository)) {
+	t.Run("CreateAndGet", func(t *testing.T) {
+		users, tokens := newRepos(t)
+		ctx := context.Background()
+
+		user := newUser(t, users, "alice@example.com")
+		token := newToken(t, tokens, user.ID, "", time.Hour)
+		assert.NotEmpty(t, token.ID)
+		assert.NotEmpty(t, token.FamilyID)
+		assert.False(t, token.CreatedAt.IsZero())
+
+		found, err := tokens.GetRefreshToken(ctx, token.TokenHash)
+		require.NoError(t, err)
+		assert.Equal(t, token.ID, found.ID)
+		assert.Equal(t, user.ID, found.UserID)
+		assert.Equal(t, token.FamilyID, found.FamilyID)
+		assert.WithinDuration(t, token.ExpiresAt, found.ExpiresAt, time.Millisecond)
+		assert.True(t, found.IsValid())
+
+		_, err = tokens.GetRefreshToken(ctx, "unknown-hash")
+		assert.ErrorIs(t, err, domain.ErrInvalidToken)
+	})
+
+	t.Run("GetBySelector", func(t *testing.T) {
+		users, tokens := newRepos(t)
+		ctx := context.Background()
+
+		user := newUser(t, users, "alice@example.com")
+		token := newToken(t, tokens, user.ID, "selector-1", time.Hour)
+
+		found, err := tokens.GetRefreshTokenBySelector(ctx, "selector-1", token.TokenHash)
+		require.NoError(t, err)
+		assert.Equal(t, token.ID, found.ID)
+
+		_, err = tokens.GetRefreshTokenBySelector(ctx, "selector-1", "wrong-hash")
+		assert.ErrorIs(t, err, domain.ErrInvalidToken)
+
+		_, err = tokens.GetRefreshTokenBySelector(ctx, "unknown-selector", token.TokenHash)
+		assert.ErrorIs(t, err, domain.ErrInvalidToken)
+	})
+
+	t.Run("RevokeIsIdempotent", func(t *testing.T) {
+		users, tokens := newRepos(t)
+		ctx := context.Background()
+
+		user := newUser(t, users, "alice@example.com")
+		token := newToken(t, tokens, user.ID, "", time.Hour)
+
+		require.NoError(t, tokens.RevokeRefreshToken(ctx, token.TokenHash))
+		first, err := tokens.GetRefreshToken(ctx, token.TokenHash)
+		require.NoError(t, err)
+		require.True(t, first.IsRevoked())
+
+		// A second revoke neither fails nor moves revoked_at
+		require.NoError(t, tokens.RevokeRefreshToken(ctx, token.TokenHash))
+		second, err := tokens.GetRefreshToken(ctx, token.TokenHash)
+		require.NoError(t, err)
+		assert.True(t, first.RevokedAt.Equal(*second.RevokedAt))
+
+		assert.NoError(t, tokens.RevokeRefreshToken(ctx, "unknown-hash"))
+	})
+
+	t.Run("Rotate", func(t *testing.T) {
+		users, tokens := newRepos(t)
+		ctx := context.Background()
+
+		user := newUser(t, users, "alice@example.com")
+		old := newToken(t, tokens, user.ID, "", time.Hour)
+
+		next := successor(user.ID, old)
+		require.NoError(t, tokens.RotateRefreshToken(ctx, old.ID, next))
+		assert.NotEmpty(t, next.ID)
+		assert.Equal(t, old.FamilyID, next.FamilyID)
+
+		rotated, err := tokens.GetRefreshToken(ctx, old.TokenHash)
+		require.NoError(t, err)
+		assert.True(t, rotated.IsRevoked())
+		assert.True(t, rotated.IsRotated())
+
+		stored, err := tokens.GetRefreshToken(ctx, next.TokenHash)
+		require.NoError(t, err)
+		assert.Equal(t, old.ID, stored.ParentID)
+		assert.True(t, stored.IsValid())
+
+		// The old token cannot be rotated twice
+		err = tokens.RotateRefreshToken(ctx, old.ID, successor(user.ID, old))
+		assert.ErrorIs(t, err, domain.ErrTokenRevoked)
+
+		err = tokens.RotateRefreshToken(ctx, uuid.NewString(), successor(user.ID, old))
+		assert.ErrorIs(t, err, domain.ErrTokenRevoked)
+	})
+
+	t.Run("ConcurrentRotateHasOneWinner", func(t *testing.T) {
+		users, tokens := newRepos(t)
+		ctx := context.Background()
+
+		user := newUser(t, users, "alice@example.com")
+		old := newToken(t, tokens, user.ID, "", time.Hour)
+
+		const callers = 8
+		errs := make(chan error, callers)
+
+		var wg sync.WaitGroup
+		for i := 0; i < callers; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				errs <- tokens.RotateRefreshToken(ctx, old.ID, successor(user.ID, old))
+			}()
+		}
+		wg.Wait()
+		close(errs)
+
+		succeeded := 0
+		for err := range errs {
+			if err == nil {
+				succeeded++
+				continue
+			}
+			assert.ErrorIs(t, err, domain.ErrTokenRevoked)
+		}
+		assert.Equal(t, 1, succeeded)
+
+		valid, err := tokens.GetValidRefreshTokens(ctx, user.ID)
+		require.NoError(t, err)
+		assert.Len(t, valid, 1)
+	})
+
+	t.Run("RevokeTokenFamily", func(t *testing.T) {
+		users, tokens := newRepos(t)
+		ctx := context.Background()
+
+		user := newUser(t, users, "alice@example.com")
+		first := newToken(t, tokens, user.ID, "", time.Hour)
+		next := successor(user.ID, first)
+		require.NoError(t, tokens.RotateRefreshToken(ctx, first.ID, next))
+		other := newToken(t, tokens, user.ID, "", time.Hour)
+
+		require.NoError(t, tokens.RevokeTokenFamily(ctx, first.FamilyID))
+
+		valid, err := tokens.GetValidRefreshTokens(ctx, user.ID)
+		require.NoError(t, err)
+		require.Len(t, valid, 1)
+		assert.Equal(t, other.ID, valid[0].ID)
+	})
+
+	t.Run("RevokeAllUserTokens", func(t *testing.T) {
+		users, tokens := newRepos(t)
+		ctx := context.Background()
+
+		alice := newUser(t, users, "alice@example.com")
+		bob := newUser(t, users, "bob@example.com")
+		newToken(t, tokens, alice.ID, "", time.Hour)
+		newToken(t, tokens, alice.ID, "", time.Hour)
+		newToken(t, tokens, bob.ID, "", time.Hour)
+
+		require.NoError(t, tokens.RevokeAllUserTokens(ctx, alice.ID))
+		require.NoError(t, tokens.RevokeAllUserTokens(ctx, alice.ID))
+
+		valid, err := tokens.GetValidRefreshTokens(ctx, alice.ID)
+		require.NoError(t, err)
+		assert.Empty(t, valid)
+
+		valid, err = tokens.GetValidRefreshTokens(ctx, bob.ID)
+		require.NoError(t, err)
+		assert.Len(t, valid, 1)
+	})
+
+	t.Run("GetValidFiltersExpiredAndRevoked", func(t *testing.T) {
+		users, tokens := newRepos(t)
+		ctx := context.Background()
+
+		user := newUser(t, users, "alice@example.com")
+		live := newToken(t, tokens, user.ID, "", time.Hour)
+		newToken(t, tokens, user.ID, "", -time.Minute)
+		revoked := newToken(t, tokens, user.ID, "", time.Hour)
+		require.NoError(t, tokens.RevokeRefreshToken(ctx, revoked.TokenHash))
+
+		valid, err := tokens.GetValidRefreshTokens(ctx, user.ID)
+		require.NoError(t, err)
+		require.Len(t, valid, 1)
+		assert.Equal(t, live.ID, valid[0].ID)
+
+		valid, err = tokens.GetValidRefreshTokens(ctx, uuid.NewString())
+		require.NoError(t, err)
+		assert.Empty(t, valid)
+	})
+}
+
+func newToken(t *testing.T, repo ports.TokenRepository, userID, selector string, ttl time.Duration) *domain.RefreshToken {
+	t.Helper()
+
+	token := &domain.RefreshToken{
+		UserID:    userID,
+		TokenHash: uuid.NewString(),
+		Selector:  selector,
+		ExpiresAt: time.Now().Add(ttl),
+	}
+	require.NoError(t, repo.CreateRefreshToken(context.Background(), token))
+
+	return token
+}
+
+func successor(userID string, parent *domain.RefreshToken) *domain.RefreshToken {
+	return &domain.RefreshToken{
+		UserID:    userID,
+		TokenHash: uuid.NewString(),
+		FamilyID:  parent.FamilyID,
+		ParentID:  parent.ID,
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+}
@@ -0,0 +1,104 @@
+// Package storagetest is a conformance suite for ports.UserRepository and
+// ports.TokenRepository. Every storage adapter runs it, so core can rely on
+// the same behaviour whichever one is configured.
+package storagetest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/natrayanp/GoMicro/auth-service/internal/domain"
+	"github.com/natrayanp/GoMicro/auth-service/internal/ports"
+)
+
+// RunUserRepositoryTests runs the user repository contract. newRepo must
+// return a repository over empty storage for every call.
+func RunUserRepositoryTests(t *testing.T, newRepo func(t *testing.T) ports.UserRepository) {
+	t.Run("CreateAndGet", func(t *testing.T) {
+		repo := newRepo(t)
+		ctx := context.Background()
+
+		user := newUser(t, repo, "alice@example.com")
+		assert.NotEmpty(t, user.ID)
+		assert.False(t, user.CreatedAt.IsZero())
+
+		byID, err := repo.GetUserByID(ctx, user.ID)
+		require.NoError(t, err)
+		assert.Equal(t, user.Email, byID.Email)
+		assert.Equal(t, user.PasswordHash, byID.PasswordHash)
+
+		byEmail, err := repo.GetUserByEmail(ctx, user.Email)
+		require.NoError(t, err)
+		assert.Equal(t, user.ID, byEmail.ID)
+	})
+
+	t.Run("DuplicateEmail", func(t *testing.T) {
+		repo := newRepo(t)
+
+		newUser(t, repo, "alice@example.com")
+
+		err := repo.CreateUser(context.Background(), &domain.User{Email: "alice@example.com", PasswordHash: "other"})
+		assert.ErrorIs(t, err, domain.ErrUserExists)
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		repo := newRepo(t)
+		ctx := context.Background()
+
+		_, err := repo.GetUserByID(ctx, uuid.NewString())
+		assert.ErrorIs(t, err, domain.ErrUserNotFound)
+
+		_, err = repo.GetUserByID(ctx, "not-a-uuid")
+		assert.ErrorIs(t, err, domain.ErrUserNotFound)
+
+		_, err = repo.GetUserByEmail(ctx, "nobody@example.com")
+		assert.ErrorIs(t, err, domain.ErrUserNotFound)
+	})
+
+	t.Run("UpdatePassword", func(t *testing.T) {
+		repo := newRepo(t)
+		ctx := context.Background()
+
+		user := newUser(t, repo, "alice@example.com")
+
+		require.NoError(t, repo.UpdateUserPassword(ctx, user.ID, "new-hash"))
+
+		updated, err := repo.GetUserByID(ctx, user.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "new-hash", updated.PasswordHash)
+
+		err = repo.UpdateUserPassword(ctx, uuid.NewString(), "new-hash")
+		assert.ErrorIs(t, err, domain.ErrUserNotFound)
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		repo := newRepo(t)
+		ctx := context.Background()
+
+		user := newUser(t, repo, "alice@example.com")
+
+		require.NoError(t, repo.DeleteUser(ctx, user.ID))
+
+		_, err := repo.GetUserByID(ctx, user.ID)
+		assert.ErrorIs(t, err, domain.ErrUserNotFound)
+
+		err = repo.DeleteUser(ctx, user.ID)
+		assert.ErrorIs(t, err, domain.ErrUserNotFound)
+
+		// The email is free again
+		newUser(t, repo, "alice@example.com")
+	})
+}
+
+func newUser(t *testing.T, repo ports.UserRepository, email string) *domain.User {
+	t.Helper()
+
+	user := &domain.User{Email: email, PasswordHash: "hash-" + email}
+	require.NoError(t, repo.CreateUser(context.Background(), user))
+
+	return user
+}
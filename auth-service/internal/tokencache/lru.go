@@ -0,0 +1,148 @@
+// Package tokencache keeps recently validated access tokens in memory.
+package tokencache
+
+import (
+	"container/list"
+	"sync"
+	"time"
+
+	"github.com/natrayanp/GoMicro/auth-service/internal/domain"
+	"github.com/natrayanp/GoMicro/auth-service/internal/ports"
+)
+
+// LRU is a bounded ValidationCache that evicts the least recently used
+// token once full. Entries live for the TTL, or until the token expires if
+// that is sooner.
+type LRU struct {
+	size int
+	ttl  time.Duration
+
+	mu         sync.Mutex
+	order      *list.List // front is most recently used
+	items      map[string]*list.Element
+	byUser     map[string]map[string]struct{}
+	generation uint64 // bumped by InvalidateUser
+}
+
+type entry struct {
+	key     string
+	claims  domain.TokenClaims
+	expires time.Time
+}
+
+var _ ports.ValidationCache = (*LRU)(nil)
+
+// MaxTTL caps the TTL of every cache. Invalidation only reaches the local
+// instance, so this bounds how long another instance can serve a revoked
+// token from its cache.
+const MaxTTL = time.Minute
+
+// NewLRU creates a cache holding at most size tokens for up to ttl each,
+// but no longer than MaxTTL
+func NewLRU(size int, ttl time.Duration) *LRU {
+	if ttl > MaxTTL {
+		ttl = MaxTTL
+	}
+	return &LRU{
+		size:   size,
+		ttl:    ttl,
+		order:  list.New(),
+		items:  make(map[string]*list.Element),
+		byUser: make(map[string]map[string]struct{}),
+	}
+}
+
+// Get implements ValidationCache.Get
+func (c *LRU) Get(tokenHash string) (*domain.TokenClaims, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.items[tokenHash]
+	if !ok {
+		return nil, false
+	}
+
+	e := elem.Value.(*entry)
+	if !time.Now().Before(e.expires) {
+		c.remove(elem)
+		return nil, false
+	}
+
+	c.order.MoveToFront(elem)
+	claims := e.claims
+	return &claims, true
+}
+
+// Generation implements ValidationCache.Generation
+func (c *LRU) Generation() uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.generation
+}
+
+// Add implements ValidationCache.Add. Any invalidation since gen, not just
+// one of this user, skips the add; that only costs a cache miss.
+func (c *LRU) Add(tokenHash string, claims *domain.TokenClaims, gen uint64) {
+	expires := time.Now().Add(c.ttl)
+	if !claims.ExpiresAt.IsZero() && claims.ExpiresAt.Before(expires) {
+		expires = claims.ExpiresAt
+	}
+	if !time.Now().Before(expires) || c.size <= 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if gen != c.generation {
+		return
+	}
+
+	if elem, ok := c.items[tokenHash]; ok {
+		c.remove(elem)
+	}
+
+	c.items[tokenHash] = c.order.PushFront(&entry{key: tokenHash, claims: *claims, expires: expires})
+	if c.byUser[claims.Subject] == nil {
+		c.byUser[claims.Subject] = make(map[string]struct{})
+	}
+	c.byUser[claims.Subject][tokenHash] = struct{}{}
+
+	for c.order.Len() > c.size {
+		c.remove(c.order.Back())
+	}
+}
+
+// InvalidateUser implements ValidationCache.InvalidateUser
+func (c *LRU) InvalidateUser(userID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.generation++
+
+	for key := range c.byUser[userID] {
+		c.remove(c.items[key])
+	}
+}
+
+// Len returns the number of cached tokens, including expired ones not yet
+// evicted
+func (c *LRU) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.order.Len()
+}
+
+// remove drops an element; the caller holds mu
+func (c *LRU) remove(elem *list.Element) {
+	e := c.order.Remove(elem).(*entry)
+	delete(c.items, e.key)
+
+	keys := c.byUser[e.claims.Subject]
+	delete(keys, e.key)
+	if len(keys) == 0 {
+		delete(c.byUser, e.claims.Subject)
+	}
+}
@@ -0,0 +1,297 @@
+// Package authclient is the Go SDK for the auth service. Client wraps the
+// gRPC API with deadlines and retries, Session keeps an access token fresh,
+// and the verifiers and middleware check access tokens in downstream
+// services, either locally against the signing keys or remotely through
+// Validate.
+package authclient
+
+import (
+	"context"
+	"math/rand/v2"
+	"time"
+
+	pb "github.com/natrayanp/GoMicro/auth-service/proto/auth/v1"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	defaultTimeout     = 5 * time.Second
+	defaultMaxAttempts = 3
+	defaultBackoff     = 100 * time.Millisecond
+	maxBackoff         = 5 * time.Second
+)
+
+// Client calls the auth service. Every call gets its own deadline, and
+// calls that are safe to repeat are retried while the service is
+// unavailable.
+type Client struct {
+	rpc         pb.AuthServiceClient
+	conn        *grpc.ClientConn // set when Dial created the connection
+	timeout     time.Duration
+	maxAttempts int
+	backoff     time.Duration
+}
+
+// Option configures a Client
+type Option func(*clientOptions)
+
+type clientOptions struct {
+	timeout     time.Duration
+	maxAttempts int
+	backoff     time.Duration
+	dialOptions []grpc.DialOption
+}
+
+// WithTimeout sets the deadline of each attempt. The default is 5s, which
+// is also used for a timeout that is not positive.
+func WithTimeout(timeout time.Duration) Option {
+	return func(o *clientOptions) {
+		o.timeout = timeout
+	}
+}
+
+// WithRetries sets how many attempts a retryable call gets and the first
+// backoff, doubled after every attempt up to 5s. The default is 3 attempts
+// from 100ms; 1 disables retries and a backoff of 0 retries immediately.
+func WithRetries(maxAttempts int, backoff time.Duration) Option {
+	return func(o *clientOptions) {
+		o.maxAttempts = maxAttempts
+		o.backoff = backoff
+	}
+}
+
+// WithDialOptions passes options such as transport credentials to Dial
+func WithDialOptions(opts ...grpc.DialOption) Option {
+	return func(o *clientOptions) {
+		o.dialOptions = append(o.dialOptions, opts...)
+	}
+}
+
+// New creates a client over an existing connection, which the caller keeps
+// ownership of
+func New(conn grpc.ClientConnInterface, opts ...Option) *Client {
+	o := newClientOptions(opts)
+
+	return &Client{
+		rpc:         pb.NewAuthServiceClient(conn),
+		timeout:     o.timeout,
+		maxAttempts: o.maxAttempts,
+		backoff:     o.backoff,
+	}
+}
+
+// Dial connects to the auth service at target. Transport credentials must
+// be given with WithDialOptions; Close releases the connection.
+func Dial(target string, opts ...Option) (*Client, error) {
+	o := newClientOptions(opts)
+
+	conn, err := grpc.NewClient(target, o.dialOptions...)
+	if err != nil {
+		return nil, err
+	}
+
+	client := New(conn, opts...)
+	client.conn = conn
+	return client, nil
+}
+
+func newClientOptions(opts []Option) clientOptions {
+	o := clientOptions{
+		timeout:     defaultTimeout,
+		maxAttempts: defaultMaxAttempts,
+		backoff:     defaultBackoff,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.timeout <= 0 {
+		o.timeout = defaultTimeout
+	}
+	if o.maxAttempts < 1 {
+		o.maxAttempts = 1
+	}
+	o.backoff = min(max(o.backoff, 0), maxBackoff)
+	return o
+}
+
+// Close closes the connection opened by Dial. It is a no-op for clients
+// created with New.
+func (c *Client) Close() error {
+	if c.conn == nil {
+		return nil
+	}
+	return c.conn.Close()
+}
+
+// Tokens is an access/refresh token pair
+type Tokens struct {
+	AccessToken  string
+	RefreshToken string
+	ExpiresIn    time.Duration // access token lifetime when issued
+	ExpiresAt    time.Time     // when the access token expires
+}
+
+func newTokens(access, refresh string, expiresIn int64) *Tokens {
+	lifetime := time.Duration(expiresIn) * time.Second
+	return &Tokens{
+		AccessToken:  access,
+		RefreshToken: refresh,
+		ExpiresIn:    lifetime,
+		ExpiresAt:    time.Now().Add(lifetime),
+	}
+}
+
+// Register creates a user and returns its ID. It is not retried, since a
+// lost response may hide a created user.
+func (c *Client) Register(ctx context.Context, email, password string) (string, error) {
+	var resp *pb.RegisterResponse
+	err := c.invoke(ctx, false, func(ctx context.Context) (err error) {
+		resp, err = c.rpc.Register(ctx, &pb.RegisterRequest{Email: email, Password: password})
+		return err
+	})
+	if err != nil {
+		return "", err
+	}
+	return resp.UserId, nil
+}
+
+// Login exchanges credentials for tokens. It is not retried so failures
+// are never counted twice towards an account lockout.
+func (c *Client) Login(ctx context.Context, email, password string) (*Tokens, error) {
+	var resp *pb.LoginResponse
+	err := c.invoke(ctx, false, func(ctx context.Context) (err error) {
+		resp, err = c.rpc.Login(ctx, &pb.LoginRequest{Email: email, Password: password})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return newTokens(resp.AccessToken, resp.RefreshToken, resp.ExpiresIn), nil
+}
+
+// Refresh rotates a refresh token. It is never retried: the service
+// treats a second use of the same refresh token as theft and ends the
+// session.
+func (c *Client) Refresh(ctx context.Context, refreshToken string) (*Tokens, error) {
+	var resp *pb.RefreshResponse
+	err := c.invoke(ctx, false, func(ctx context.Context) (err error) {
+		resp, err = c.rpc.Refresh(ctx, &pb.RefreshRequest{RefreshToken: refreshToken})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return newTokens(resp.AccessToken, resp.RefreshToken, resp.ExpiresIn), nil
+}
+
+// Validate asks the service whether an access token is valid and returns
+// its claims. A rejected token returns ErrTokenExpired, ErrTokenRevoked or
+// ErrInvalidToken; any other error means the token could not be checked.
+func (c *Client) Validate(ctx context.Context, accessToken string) (*Claims, error) {
+	var resp *pb.ValidateResponse
+	err := c.invoke(ctx, true, func(ctx context.Context) (err error) {
+		resp, err = c.rpc.Validate(ctx, &pb.ValidateRequest{Token: accessToken})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if !resp.Valid {
+		switch resp.FailureReason {
+		case pb.ValidateFailureReason_VALIDATE_FAILURE_REASON_EXPIRED:
+			return nil, ErrTokenExpired
+		case pb.ValidateFailureReason_VALIDATE_FAILURE_REASON_REVOKED:
+			return nil, ErrTokenRevoked
+		default:
+			return nil, ErrInvalidToken
+		}
+	}
+
+	return &Claims{
+		UserID:    resp.UserId,
+		SessionID: resp.SessionId,
+		Roles:     resp.Roles,
+		Scopes:    resp.Scopes,
+		IssuedAt:  unixTime(resp.IssuedAt),
+		ExpiresAt: unixTime(resp.ExpiresAt),
+	}, nil
+}
+
+// unixTime converts Unix seconds, treating 0 as unset
+func unixTime(sec int64) time.Time {
+	if sec == 0 {
+		return time.Time{}
+	}
+	return time.Unix(sec, 0)
+}
+
+// Logout revokes a refresh token. Access tokens issued with it stay valid
+// until they expire; use Session.Logout to revoke both.
+func (c *Client) Logout(ctx context.Context, refreshToken string) error {
+	return c.logout(ctx, &pb.LogoutRequest{RefreshToken: refreshToken})
+}
+
+func (c *Client) logout(ctx context.Context, req *pb.LogoutRequest) error {
+	return c.invoke(ctx, true, func(ctx context.Context) error {
+		_, err := c.rpc.Logout(ctx, req)
+		return err
+	})
+}
+
+// GetUser fetches a user record with the caller's access token
+func (c *Client) GetUser(ctx context.Context, accessToken, userID string) (*pb.User, error) {
+	ctx = metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+accessToken)
+
+	var resp *pb.GetUserResponse
+	err := c.invoke(ctx, true, func(ctx context.Context) (err error) {
+		resp, err = c.rpc.GetUser(ctx, &pb.GetUserRequest{UserId: userID})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp.User, nil
+}
+
+// invoke runs call with a per-attempt deadline, retrying it with
+// jittered exponential backoff when retry is set and the error is
+// transient
+func (c *Client) invoke(ctx context.Context, retry bool, call func(context.Context) error) error {
+	backoff := c.backoff
+
+	for attempt := 1; ; attempt++ {
+		attemptCtx, cancel := context.WithTimeout(ctx, c.timeout)
+		err := call(attemptCtx)
+		cancel()
+
+		if err == nil || !retry || attempt >= c.maxAttempts || !retryable(ctx, err) {
+			return err
+		}
+
+		wait := backoff/2 + rand.N(backoff/2+1)
+		select {
+		case <-time.After(wait):
+		case <-ctx.Done():
+			return err
+		}
+		backoff = min(backoff*2, maxBackoff)
+	}
+}
+
+// retryable reports whether err is transient: the service was unavailable,
+// or a single attempt ran out of time while the caller still has some
+func retryable(ctx context.Context, err error) bool {
+	switch status.Code(err) {
+	case codes.Unavailable:
+		return true
+	case codes.DeadlineExceeded:
+		return ctx.Err() == nil
+	default:
+		return false
+	}
+}
@@ -0,0 +1,31 @@
+package authclient
+
+import (
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/status"
+)
+
+// Reason returns the machine-readable reason the service attached to an
+// error, such as "TOKEN_EXPIRED" or "ACCOUNT_LOCKED", or "" if there is
+// none
+func Reason(err error) string {
+	for _, detail := range status.Convert(err).Details() {
+		if info, ok := detail.(*errdetails.ErrorInfo); ok {
+			return info.Reason
+		}
+	}
+	return ""
+}
+
+// RetryDelay returns how long the service asked the caller to wait, for
+// locked accounts and rate limited calls
+func RetryDelay(err error) (time.Duration, bool) {
+	for _, detail := range status.Convert(err).Details() {
+		if info, ok := detail.(*errdetails.RetryInfo); ok {
+			return info.RetryDelay.AsDuration(), true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,239 @@
+package authclient
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// minJWKSRefetch limits refetches triggered by unknown key IDs, so tokens
+// with made-up kids cannot hammer the JWKS endpoint
+const minJWKSRefetch = 10 * time.Second
+
+// jwk is one entry of a JWKS document
+type jwk struct {
+	KeyType   string `json:"kty"`
+	KeyID     string `json:"kid"`
+	Algorithm string `json:"alg"`
+	N         string `json:"n"`
+	E         string `json:"e"`
+	Curve     string `json:"crv"`
+	X         string `json:"x"`
+	Y         string `json:"y"`
+}
+
+// publicKey is a parsed JWK and the only algorithm it may verify
+type publicKey struct {
+	alg string
+	key interface{}
+}
+
+// jwksKeys caches the keys of a JWKS document by key ID
+type jwksKeys struct {
+	url     string
+	client  *http.Client
+	refresh time.Duration
+
+	mu        sync.Mutex
+	keys      map[string]publicKey
+	fetchedAt time.Time
+	inflight  *jwksFetch // nil unless a fetch is running
+}
+
+// jwksFetch is one fetch of the document, shared by every lookup that
+// needs it
+type jwksFetch struct {
+	done chan struct{}
+	err  error // set before done is closed
+}
+
+func newJWKSKeys(url string, client *http.Client, refresh time.Duration) *jwksKeys {
+	return &jwksKeys{url: url, client: client, refresh: refresh}
+}
+
+// keyFunc returns the verification key for a token's kid, refetching the
+// document when it is stale or does not know the kid
+func (k *jwksKeys) keyFunc(ctx context.Context, token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+
+	key, err := k.lookup(ctx, kid)
+	if err != nil {
+		return nil, err
+	}
+
+	if key.alg != "" && key.alg != token.Method.Alg() {
+		return nil, fmt.Errorf("key %q is for %s, token uses %s", kid, key.alg, token.Method.Alg())
+	}
+	return key.key, nil
+}
+
+// lookup returns the key for kid. Known keys are served straight away, even
+// while a stale document is being refetched in the background; unknown keys
+// wait for the fetch.
+func (k *jwksKeys) lookup(ctx context.Context, kid string) (publicKey, error) {
+	k.mu.Lock()
+	key, known := k.keys[kid]
+	age := time.Since(k.fetchedAt)
+
+	if known {
+		if age >= k.refresh {
+			k.startFetch(ctx)
+		}
+		k.mu.Unlock()
+		return key, nil
+	}
+	if k.keys != nil && age < minJWKSRefetch {
+		k.mu.Unlock()
+		return publicKey{}, fmt.Errorf("unknown key %q", kid)
+	}
+	f := k.startFetch(ctx)
+	k.mu.Unlock()
+
+	select {
+	case <-f.done:
+	case <-ctx.Done():
+		return publicKey{}, fmt.Errorf("%w: %v", ErrUnavailable, ctx.Err())
+	}
+	if f.err != nil {
+		return publicKey{}, fmt.Errorf("%w: %v", ErrUnavailable, f.err)
+	}
+
+	k.mu.Lock()
+	key, known = k.keys[kid]
+	k.mu.Unlock()
+	if !known {
+		return publicKey{}, fmt.Errorf("unknown key %q", kid)
+	}
+	return key, nil
+}
+
+// startFetch refetches the document unless a fetch is already running and
+// returns that fetch. The fetch outlives the caller's cancellation, since
+// other lookups may be waiting on it; the HTTP client's timeout bounds it.
+// The caller holds k.mu.
+func (k *jwksKeys) startFetch(ctx context.Context) *jwksFetch {
+	if k.inflight != nil {
+		return k.inflight
+	}
+
+	f := &jwksFetch{done: make(chan struct{})}
+	k.inflight = f
+
+	go func() {
+		keys, err := k.fetch(context.WithoutCancel(ctx))
+
+		k.mu.Lock()
+		// On failure keep verifying with the keys we have
+		if err == nil {
+			k.keys = keys
+			k.fetchedAt = time.Now()
+		}
+		k.inflight = nil
+		k.mu.Unlock()
+
+		f.err = err
+		close(f.done)
+	}()
+	return f
+}
+
+func (k *jwksKeys) fetch(ctx context.Context) (map[string]publicKey, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, k.url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := k.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetch JWKS: %s", resp.Status)
+	}
+
+	var doc struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("decode JWKS: %w", err)
+	}
+
+	keys := make(map[string]publicKey, len(doc.Keys))
+	for _, j := range doc.Keys {
+		key, err := j.publicKey()
+		if err != nil {
+			// Skip keys we cannot use rather than rejecting the whole set
+			continue
+		}
+		keys[j.KeyID] = publicKey{alg: j.Algorithm, key: key}
+	}
+	return keys, nil
+}
+
+// publicKey decodes the RSA, P-256 and Ed25519 keys the service publishes
+func (j jwk) publicKey() (interface{}, error) {
+	switch j.KeyType {
+	case "RSA":
+		n, err := decodeSegment(j.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeSegment(j.E)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}, nil
+
+	case "EC":
+		if j.Curve != "P-256" {
+			return nil, fmt.Errorf("unsupported curve %q", j.Curve)
+		}
+		x, err := decodeSegment(j.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeSegment(j.Y)
+		if err != nil {
+			return nil, err
+		}
+		if len(x) != 32 || len(y) != 32 {
+			return nil, errors.New("invalid P-256 coordinates")
+		}
+		point := append(append([]byte{4}, x...), y...)
+		return ecdsa.ParseUncompressedPublicKey(elliptic.P256(), point)
+
+	case "OKP":
+		if j.Curve != "Ed25519" {
+			return nil, fmt.Errorf("unsupported curve %q", j.Curve)
+		}
+		x, err := decodeSegment(j.X)
+		if err != nil {
+			return nil, err
+		}
+		if len(x) != ed25519.PublicKeySize {
+			return nil, errors.New("invalid Ed25519 key")
+		}
+		return ed25519.PublicKey(x), nil
+
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", j.KeyType)
+	}
+}
+
+func decodeSegment(s string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(s)
+}
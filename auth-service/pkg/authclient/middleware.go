@@ -0,0 +1,173 @@
+package authclient
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+type claimsKey struct{}
+
+// ContextWithClaims records verified claims for the request
+func ContextWithClaims(ctx context.Context, claims *Claims) context.Context {
+	return context.WithValue(ctx, claimsKey{}, claims)
+}
+
+// ClaimsFromContext returns the claims stored by the middleware, if any
+func ClaimsFromContext(ctx context.Context) (*Claims, bool) {
+	claims, ok := ctx.Value(claimsKey{}).(*Claims)
+	return claims, ok
+}
+
+// UserIDFromContext returns the authenticated user ID, if any
+func UserIDFromContext(ctx context.Context) (string, bool) {
+	claims, ok := ClaimsFromContext(ctx)
+	if !ok {
+		return "", false
+	}
+	return claims.UserID, true
+}
+
+// InterceptorOption configures the server interceptors
+type InterceptorOption func(*interceptorOptions)
+
+type interceptorOptions struct {
+	public map[string]bool
+}
+
+// WithPublicMethods lets full method names, such as
+// "/grpc.health.v1.Health/Check", through without a token
+func WithPublicMethods(methods ...string) InterceptorOption {
+	return func(o *interceptorOptions) {
+		for _, m := range methods {
+			o.public[m] = true
+		}
+	}
+}
+
+func newInterceptorOptions(opts []InterceptorOption) interceptorOptions {
+	o := interceptorOptions{public: make(map[string]bool)}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// UnaryServerInterceptor rejects calls without a valid Bearer access token
+// and stores the verified claims in the context
+func UnaryServerInterceptor(v Verifier, opts ...InterceptorOption) grpc.UnaryServerInterceptor {
+	o := newInterceptorOptions(opts)
+
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if o.public[info.FullMethod] {
+			return handler(ctx, req)
+		}
+
+		ctx, err := verifyIncoming(ctx, v)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor is UnaryServerInterceptor for streaming calls
+func StreamServerInterceptor(v Verifier, opts ...InterceptorOption) grpc.StreamServerInterceptor {
+	o := newInterceptorOptions(opts)
+
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if o.public[info.FullMethod] {
+			return handler(srv, ss)
+		}
+
+		ctx, err := verifyIncoming(ss.Context(), v)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &claimsStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// claimsStream carries the verified context into stream handlers
+type claimsStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *claimsStream) Context() context.Context {
+	return s.ctx
+}
+
+func verifyIncoming(ctx context.Context, v Verifier) (context.Context, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	claims, err := v.Verify(ctx, bearerToken(md.Get("authorization")))
+	if err != nil {
+		logRejected(ctx, err)
+		if errors.Is(err, ErrUnavailable) {
+			return nil, status.Error(codes.Unavailable, "service unavailable")
+		}
+		return nil, status.Error(codes.Unauthenticated, "unauthorized")
+	}
+
+	return ContextWithClaims(ctx, claims), nil
+}
+
+// Middleware rejects HTTP requests without a valid Bearer access token
+// with 401, or 503 when tokens cannot be checked, and stores the verified
+// claims in the request context
+func Middleware(v Verifier) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			claims, err := v.Verify(r.Context(), bearerToken(r.Header.Values("Authorization")))
+			if err != nil {
+				logRejected(r.Context(), err)
+			}
+			switch {
+			case errors.Is(err, ErrUnavailable):
+				http.Error(w, "service unavailable", http.StatusServiceUnavailable)
+				return
+			case errors.Is(err, ErrMissingToken):
+				w.Header().Set("WWW-Authenticate", "Bearer")
+				http.Error(w, "unauthorized", http.StatusUnauthorized)
+				return
+			case err != nil:
+				w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+				http.Error(w, "unauthorized", http.StatusUnauthorized)
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(ContextWithClaims(r.Context(), claims)))
+		})
+	}
+}
+
+// logRejected logs why a request was rejected. Callers only get a fixed
+// message, since the error can carry verifier or transport details.
+func logRejected(ctx context.Context, err error) {
+	level := slog.LevelDebug
+	if errors.Is(err, ErrUnavailable) {
+		level = slog.LevelWarn
+	}
+	slog.Default().Log(ctx, level, "authclient: request rejected", "error", err)
+}
+
+// bearerToken returns the token of the first "Bearer <token>" value
+func bearerToken(values []string) string {
+	for _, value := range values {
+		scheme, token, found := strings.Cut(value, " ")
+		if found && strings.EqualFold(scheme, "bearer") {
+			if token = strings.TrimSpace(token); token != "" {
+				return token
+			}
+		}
+	}
+	return ""
+}
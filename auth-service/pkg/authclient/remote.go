@@ -0,0 +1,105 @@
+package authclient
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// RemoteVerifier checks access tokens by calling Validate, caching valid
+// results briefly. Unlike LocalVerifier it sees revocations, up to the
+// cache TTL.
+type RemoteVerifier struct {
+	client *Client
+	ttl    time.Duration
+	size   int
+
+	mu    sync.Mutex
+	cache map[[sha256.Size]byte]remoteEntry
+}
+
+type remoteEntry struct {
+	claims  Claims
+	expires time.Time
+}
+
+// NewRemoteVerifier verifies tokens through client. Only WithCache applies.
+func NewRemoteVerifier(client *Client, opts ...VerifierOption) *RemoteVerifier {
+	o := newVerifierOptions(opts)
+
+	return &RemoteVerifier{
+		client: client,
+		ttl:    o.cacheTTL,
+		size:   o.cacheSize,
+		cache:  make(map[[sha256.Size]byte]remoteEntry),
+	}
+}
+
+// Verify implements Verifier
+func (v *RemoteVerifier) Verify(ctx context.Context, token string) (*Claims, error) {
+	if token == "" {
+		return nil, ErrMissingToken
+	}
+
+	// Key by hash so the cache never holds usable tokens
+	key := sha256.Sum256([]byte(token))
+	if claims, ok := v.cached(key); ok {
+		return claims, nil
+	}
+
+	claims, err := v.client.Validate(ctx, token)
+	switch {
+	case errors.Is(err, ErrInvalidToken), errors.Is(err, ErrTokenExpired), errors.Is(err, ErrTokenRevoked):
+		return nil, err
+	case err != nil:
+		return nil, fmt.Errorf("%w: %v", ErrUnavailable, err)
+	}
+
+	v.store(key, claims)
+	return claims, nil
+}
+
+func (v *RemoteVerifier) cached(key [sha256.Size]byte) (*Claims, bool) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	entry, ok := v.cache[key]
+	if !ok || time.Now().After(entry.expires) {
+		return nil, false
+	}
+	claims := entry.claims
+	return &claims, true
+}
+
+// store caches valid claims for the TTL, or until the token expires if
+// that is sooner
+func (v *RemoteVerifier) store(key [sha256.Size]byte, claims *Claims) {
+	if v.ttl <= 0 {
+		return
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	now := time.Now()
+	if len(v.cache) >= v.size {
+		for k, entry := range v.cache {
+			if now.After(entry.expires) {
+				delete(v.cache, k)
+			}
+		}
+		// Still full of live entries: start over rather than grow
+		if len(v.cache) >= v.size {
+			clear(v.cache)
+		}
+	}
+
+	expires := now.Add(v.ttl)
+	if !claims.ExpiresAt.IsZero() && claims.ExpiresAt.Before(expires) {
+		expires = claims.ExpiresAt
+	}
+	v.cache[key] = remoteEntry{claims: *claims, expires: expires}
+}
@@ -0,0 +1,87 @@
+package authclient
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	pb "github.com/natrayanp/GoMicro/auth-service/proto/auth/v1"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// maxRefreshMargin is how long before expiry a session refreshes its
+// access token at the latest
+const maxRefreshMargin = 30 * time.Second
+
+// Session holds a user's tokens and refreshes the access token shortly
+// before it expires. It is safe for concurrent use; concurrent callers
+// share a single refresh.
+type Session struct {
+	client *Client
+
+	mu     sync.Mutex
+	tokens Tokens
+}
+
+// NewSession starts a session from tokens returned by Login or stored by
+// an earlier session
+func (c *Client) NewSession(tokens *Tokens) *Session {
+	return &Session{client: c, tokens: *tokens}
+}
+
+// AccessToken returns a valid access token, refreshing it first when it
+// is about to expire
+func (s *Session) AccessToken(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if time.Until(s.tokens.ExpiresAt) > refreshMargin(s.tokens.ExpiresIn) {
+		return s.tokens.AccessToken, nil
+	}
+
+	tokens, err := s.client.Refresh(ctx, s.tokens.RefreshToken)
+	if err != nil {
+		return "", err
+	}
+	s.tokens = *tokens
+
+	return s.tokens.AccessToken, nil
+}
+
+// Tokens returns the current tokens, for example to persist the session
+func (s *Session) Tokens() Tokens {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.tokens
+}
+
+// Logout revokes the session's refresh and access tokens
+func (s *Session) Logout(ctx context.Context) error {
+	tokens := s.Tokens()
+	return s.client.logout(ctx, &pb.LogoutRequest{
+		RefreshToken: tokens.RefreshToken,
+		AccessToken:  tokens.AccessToken,
+	})
+}
+
+// UnaryClientInterceptor adds the session's access token as a Bearer
+// authorization header to every outgoing call
+func (s *Session) UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		token, err := s.AccessToken(ctx)
+		if err != nil {
+			return err
+		}
+
+		ctx = metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+token)
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+}
+
+// refreshMargin is 30s, or half the lifetime of short-lived tokens
+func refreshMargin(lifetime time.Duration) time.Duration {
+	return min(maxRefreshMargin, lifetime/2)
+}
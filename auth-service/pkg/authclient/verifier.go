@@ -0,0 +1,234 @@
+package authclient
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var (
+	ErrMissingToken = errors.New("missing bearer token")
+	ErrInvalidToken = errors.New("invalid token")
+	ErrTokenExpired = errors.New("token expired")
+	// ErrTokenRevoked is only reported by RemoteVerifier; local
+	// verification cannot see revocations
+	ErrTokenRevoked = errors.New("token revoked")
+	// ErrUnavailable means the token could not be checked at all, for
+	// example because the JWKS endpoint or the auth service is down
+	ErrUnavailable = errors.New("token verification unavailable")
+)
+
+// Claims describes a verified access token
+type Claims struct {
+	UserID    string
+	TokenID   string   // empty when verified remotely
+	SessionID string   // shared by every token of one login
+	Roles     []string // such as "admin"
+	Scopes    []string
+	Issuer    string   // empty when verified remotely
+	Audience  []string // empty when verified remotely
+	IssuedAt  time.Time
+	ExpiresAt time.Time
+}
+
+// HasRole reports whether the token grants the role
+func (c *Claims) HasRole(role string) bool {
+	for _, r := range c.Roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// HasScope reports whether the token grants the scope
+func (c *Claims) HasScope(scope string) bool {
+	for _, s := range c.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// Verifier checks access tokens. Implementations return ErrInvalidToken,
+// ErrTokenExpired, ErrTokenRevoked or ErrUnavailable on failure.
+type Verifier interface {
+	Verify(ctx context.Context, token string) (*Claims, error)
+}
+
+// VerifierOption configures the verifiers. Options that do not apply to a
+// verifier are ignored.
+type VerifierOption func(*verifierOptions)
+
+type verifierOptions struct {
+	issuer      string
+	audience    string
+	leeway      time.Duration
+	httpClient  *http.Client
+	jwksRefresh time.Duration
+	cacheTTL    time.Duration
+	cacheSize   int
+}
+
+func newVerifierOptions(opts []VerifierOption) verifierOptions {
+	o := verifierOptions{
+		issuer:      "auth-service",
+		audience:    "auth-service",
+		leeway:      5 * time.Second,
+		httpClient:  &http.Client{Timeout: 10 * time.Second},
+		jwksRefresh: 5 * time.Minute,
+		cacheTTL:    30 * time.Second,
+		cacheSize:   10000,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// WithIssuer sets the required iss claim. The default is "auth-service".
+func WithIssuer(issuer string) VerifierOption {
+	return func(o *verifierOptions) {
+		o.issuer = issuer
+	}
+}
+
+// WithAudience sets the audience the token must be issued for. The
+// default is "auth-service".
+func WithAudience(audience string) VerifierOption {
+	return func(o *verifierOptions) {
+		o.audience = audience
+	}
+}
+
+// WithLeeway allows for clock skew when checking exp, nbf and iat. The
+// default is 5s.
+func WithLeeway(leeway time.Duration) VerifierOption {
+	return func(o *verifierOptions) {
+		o.leeway = leeway
+	}
+}
+
+// WithHTTPClient sets the client used to fetch the JWKS document
+func WithHTTPClient(client *http.Client) VerifierOption {
+	return func(o *verifierOptions) {
+		o.httpClient = client
+	}
+}
+
+// WithJWKSRefresh sets how often the JWKS document is refetched. Unknown
+// key IDs also trigger a refetch. The default is 5m.
+func WithJWKSRefresh(interval time.Duration) VerifierOption {
+	return func(o *verifierOptions) {
+		o.jwksRefresh = interval
+	}
+}
+
+// WithCache sets how long the remote verifier trusts a valid result and
+// how many results it keeps. Revoked tokens are accepted for up to ttl;
+// a ttl of 0 disables the cache. The default is 30s and 10000 entries.
+func WithCache(ttl time.Duration, size int) VerifierOption {
+	return func(o *verifierOptions) {
+		o.cacheTTL = ttl
+		o.cacheSize = size
+	}
+}
+
+// accessClaims is the access token wire format
+type accessClaims struct {
+	Type      string   `json:"typ"`
+	SessionID string   `json:"sid"`
+	Roles     []string `json:"roles"`
+	Scope     string   `json:"scope"` // space separated
+	jwt.RegisteredClaims
+}
+
+// LocalVerifier checks access token signatures and claims without calling
+// the auth service. Revoked tokens stay valid until they expire.
+type LocalVerifier struct {
+	keyFunc func(ctx context.Context, token *jwt.Token) (interface{}, error)
+	parser  *jwt.Parser
+}
+
+// NewHMACVerifier verifies HS256 tokens signed with the service's shared
+// JWT_SECRET
+func NewHMACVerifier(secret string, opts ...VerifierOption) *LocalVerifier {
+	key := []byte(secret)
+	return newLocalVerifier(newVerifierOptions(opts), []string{"HS256"},
+		func(context.Context, *jwt.Token) (interface{}, error) {
+			return key, nil
+		})
+}
+
+// NewJWKSVerifier verifies RS256, ES256 and EdDSA tokens against the
+// public keys served at jwksURL, such as
+// http://auth-service:8080/.well-known/jwks.json
+func NewJWKSVerifier(jwksURL string, opts ...VerifierOption) *LocalVerifier {
+	o := newVerifierOptions(opts)
+	keys := newJWKSKeys(jwksURL, o.httpClient, o.jwksRefresh)
+
+	return newLocalVerifier(o, []string{"RS256", "ES256", "EdDSA"}, keys.keyFunc)
+}
+
+func newLocalVerifier(o verifierOptions, methods []string, keyFunc func(context.Context, *jwt.Token) (interface{}, error)) *LocalVerifier {
+	return &LocalVerifier{
+		keyFunc: keyFunc,
+		parser: jwt.NewParser(
+			jwt.WithValidMethods(methods),
+			jwt.WithIssuer(o.issuer),
+			jwt.WithAudience(o.audience),
+			jwt.WithLeeway(o.leeway),
+			jwt.WithExpirationRequired(),
+			jwt.WithIssuedAt(),
+		),
+	}
+}
+
+// Verify implements Verifier
+func (v *LocalVerifier) Verify(ctx context.Context, token string) (*Claims, error) {
+	if token == "" {
+		return nil, ErrMissingToken
+	}
+
+	claims := &accessClaims{}
+	_, err := v.parser.ParseWithClaims(token, claims, func(t *jwt.Token) (interface{}, error) {
+		return v.keyFunc(ctx, t)
+	})
+	switch {
+	case errors.Is(err, ErrUnavailable):
+		return nil, err
+	case errors.Is(err, jwt.ErrTokenExpired):
+		return nil, ErrTokenExpired
+	case err != nil:
+		return nil, ErrInvalidToken
+	}
+
+	// Refresh tokens are signed with the same keys but must not be accepted
+	if claims.Type != "access" || claims.Subject == "" {
+		return nil, ErrInvalidToken
+	}
+
+	return &Claims{
+		UserID:    claims.Subject,
+		TokenID:   claims.ID,
+		SessionID: claims.SessionID,
+		Roles:     claims.Roles,
+		Scopes:    strings.Fields(claims.Scope),
+		Issuer:    claims.Issuer,
+		Audience:  claims.Audience,
+		IssuedAt:  timeOf(claims.IssuedAt),
+		ExpiresAt: timeOf(claims.ExpiresAt),
+	}, nil
+}
+
+func timeOf(t *jwt.NumericDate) time.Time {
+	if t == nil {
+		return time.Time{}
+	}
+	return t.Time
+}
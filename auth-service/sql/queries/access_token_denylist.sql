@@ -0,0 +1,28 @@
+-- name: RevokeAccessToken :exec
+INSERT INTO revoked_access_tokens (jti, user_id, expires_at)
+VALUES ($1, $2, $3)
+ON CONFLICT (jti) DO NOTHING;
+
+-- name: RevokeAccessTokensIssuedBefore :exec
+INSERT INTO access_token_watermarks (user_id, revoked_before, expires_at)
+VALUES ($1, $2, $3)
+ON CONFLICT (user_id) DO UPDATE
+SET revoked_before = GREATEST(access_token_watermarks.revoked_before, EXCLUDED.revoked_before),
+    expires_at = GREATEST(access_token_watermarks.expires_at, EXCLUDED.expires_at);
+
+-- name: IsAccessTokenRevoked :one
+SELECT EXISTS (
+    SELECT 1 FROM revoked_access_tokens
+    WHERE jti = sqlc.arg(jti) AND expires_at > NOW()
+) OR EXISTS (
+    SELECT 1 FROM access_token_watermarks
+    WHERE user_id = sqlc.arg(user_id) AND revoked_before > sqlc.arg(issued_at)
+) AS revoked;
+
+-- name: DeleteExpiredRevokedAccessTokens :execrows
+DELETE FROM revoked_access_tokens
+WHERE expires_at <= NOW();
+
+-- name: DeleteExpiredAccessTokenWatermarks :execrows
+DELETE FROM access_token_watermarks
+WHERE expires_at <= NOW();
@@ -0,0 +1,24 @@
+-- name: GetLoginAttempts :one
+SELECT login_key, failed_count, last_failed_at, locked_until
+FROM login_attempts
+WHERE login_key = $1;
+
+-- name: RecordFailedLogin :one
+INSERT INTO login_attempts (login_key, failed_count, last_failed_at)
+VALUES ($1, 1, NOW())
+ON CONFLICT (login_key) DO UPDATE
+SET failed_count = CASE
+        WHEN login_attempts.last_failed_at < sqlc.arg(reset_before) THEN 1
+        ELSE login_attempts.failed_count + 1
+    END,
+    last_failed_at = NOW()
+RETURNING login_key, failed_count, last_failed_at, locked_until;
+
+-- name: LockAccount :exec
+UPDATE login_attempts
+SET locked_until = $2
+WHERE login_key = $1;
+
+-- name: ResetLoginAttempts :exec
+DELETE FROM login_attempts
+WHERE login_key = $1;
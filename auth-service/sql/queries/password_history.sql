@@ -0,0 +1,20 @@
+-- name: AddPasswordHistory :exec
+INSERT INTO password_history (user_id, password_hash)
+VALUES ($1, $2);
+
+-- name: GetPasswordHistory :many
+SELECT password_hash
+FROM password_history
+WHERE user_id = $1
+ORDER BY created_at DESC
+LIMIT $2;
+
+-- name: PrunePasswordHistory :exec
+DELETE FROM password_history
+WHERE user_id = $1
+  AND id NOT IN (
+    SELECT id FROM password_history
+    WHERE user_id = $1
+    ORDER BY created_at DESC
+    LIMIT $2
+  );
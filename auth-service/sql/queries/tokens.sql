@@ -1,26 +1,41 @@
 -- name: CreateRefreshToken :one
-INSERT INTO refresh_tokens (user_id, token_hash, expires_at)
-VALUES ($1, $2, $3)
-RETURNING id, user_id, token_hash, expires_at, created_at, revoked_at;
+INSERT INTO refresh_tokens (user_id, token_hash, expires_at, selector, family_id, parent_id)
+VALUES ($1, $2, $3, $4, COALESCE(sqlc.narg(family_id)::uuid, gen_random_uuid()), sqlc.narg(parent_id))
+RETURNING id, user_id, token_hash, expires_at, created_at, revoked_at, selector, family_id, parent_id, rotated_at;
 
 -- name: GetRefreshToken :one
-SELECT id, user_id, token_hash, expires_at, created_at, revoked_at
+SELECT id, user_id, token_hash, expires_at, created_at, revoked_at, selector, family_id, parent_id, rotated_at
 FROM refresh_tokens
 WHERE token_hash = $1;
 
+-- name: GetRefreshTokenBySelector :one
+SELECT id, user_id, token_hash, expires_at, created_at, revoked_at, selector, family_id, parent_id, rotated_at
+FROM refresh_tokens
+WHERE selector = $1;
+
 -- name: RevokeRefreshToken :exec
 UPDATE refresh_tokens
 SET revoked_at = NOW()
 WHERE token_hash = $1 AND revoked_at IS NULL;
 
+-- name: MarkRefreshTokenRotated :execrows
+UPDATE refresh_tokens
+SET revoked_at = NOW(), rotated_at = NOW()
+WHERE id = $1 AND revoked_at IS NULL;
+
+-- name: RevokeTokenFamily :exec
+UPDATE refresh_tokens
+SET revoked_at = NOW()
+WHERE family_id = $1 AND revoked_at IS NULL;
+
 -- name: RevokeAllUserTokens :exec
 UPDATE refresh_tokens
 SET revoked_at = NOW()
 WHERE user_id = $1 AND revoked_at IS NULL;
 
 -- name: GetValidRefreshTokens :many
-SELECT id, user_id, token_hash, expires_at, created_at, revoked_at
+SELECT id, user_id, token_hash, expires_at, created_at, revoked_at, selector, family_id, parent_id, rotated_at
 FROM refresh_tokens
 WHERE user_id = $1 
   AND revoked_at IS NULL 
-  AND expires_at > NOW();
\ No newline at end of file
+  AND expires_at > NOW();
@@ -13,11 +13,11 @@ SELECT id, email, password_hash, created_at, updated_at
 FROM users
 WHERE id = $1;
 
--- name: UpdateUserPassword :exec
+-- name: UpdateUserPassword :execrows
 UPDATE users
 SET password_hash = $2, updated_at = NOW()
 WHERE id = $1;
 
--- name: DeleteUser :exec
+-- name: DeleteUser :execrows
 DELETE FROM users
 WHERE id = $1;
\ No newline at end of file
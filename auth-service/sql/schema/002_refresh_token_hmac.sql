@@ -0,0 +1,12 @@
+-- +goose Up
+-- Refresh tokens are now stored as HMAC-SHA256(pepper, token) hex digests.
+-- Rows written before this change hold the raw JWT, which is a bearer
+-- credential. They cannot be rehashed in SQL without the server-side
+-- pepper, so revoke them; affected clients simply log in again.
+UPDATE refresh_tokens
+SET revoked_at = NOW()
+WHERE revoked_at IS NULL
+  AND token_hash !~ '^[0-9a-f]{64}$';
+
+-- +goose Down
+-- Revoked legacy tokens cannot be brought back; nothing to undo.
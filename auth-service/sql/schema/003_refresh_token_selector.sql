@@ -0,0 +1,10 @@
+-- +goose Up
+-- Opaque refresh tokens are looked up by a non-secret selector and then
+-- matched on token_hash. JWT refresh tokens leave the selector NULL.
+ALTER TABLE refresh_tokens ADD COLUMN IF NOT EXISTS selector VARCHAR(64);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_refresh_tokens_selector ON refresh_tokens(selector);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_refresh_tokens_selector;
+ALTER TABLE refresh_tokens DROP COLUMN IF EXISTS selector;
@@ -0,0 +1,14 @@
+-- +goose Up
+-- Previous password hashes, so a password change can reject reuse of the
+-- last few passwords. Rows go away with the user.
+CREATE TABLE IF NOT EXISTS password_history (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    password_hash VARCHAR(255) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_password_history_user_id ON password_history(user_id, created_at DESC);
+
+-- +goose Down
+DROP TABLE IF EXISTS password_history;
@@ -0,0 +1,12 @@
+-- +goose Up
+-- Consecutive failed logins per user, used for temporary account lockout.
+-- A successful login deletes the row; rows go away with the user.
+CREATE TABLE IF NOT EXISTS login_attempts (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    failed_count INTEGER NOT NULL DEFAULT 0,
+    last_failed_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    locked_until TIMESTAMPTZ
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS login_attempts;
@@ -0,0 +1,23 @@
+-- +goose Up
+-- Access tokens revoked before their exp, by JWT ID. A row is useless once
+-- the token itself has expired and is pruned after expires_at.
+CREATE TABLE IF NOT EXISTS revoked_access_tokens (
+    jti TEXT PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    expires_at TIMESTAMPTZ NOT NULL,
+    revoked_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_revoked_access_tokens_expires_at ON revoked_access_tokens(expires_at);
+
+-- Per-user watermark: access tokens issued before revoked_before are
+-- invalid. expires_at is when the last of those tokens expires.
+CREATE TABLE IF NOT EXISTS access_token_watermarks (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    revoked_before TIMESTAMPTZ NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS access_token_watermarks;
+DROP TABLE IF EXISTS revoked_access_tokens;
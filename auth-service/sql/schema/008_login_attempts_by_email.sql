@@ -0,0 +1,22 @@
+-- +goose Up
+-- Track failed logins by normalized email instead of user, so unknown
+-- addresses lock out like registered ones and lockout does not reveal
+-- which emails exist. Lockout state is short-lived and is not carried over.
+DROP TABLE IF EXISTS login_attempts;
+
+CREATE TABLE IF NOT EXISTS login_attempts (
+    login_key TEXT PRIMARY KEY,
+    failed_count INTEGER NOT NULL DEFAULT 0,
+    last_failed_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    locked_until TIMESTAMPTZ
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS login_attempts;
+
+CREATE TABLE IF NOT EXISTS login_attempts (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    failed_count INTEGER NOT NULL DEFAULT 0,
+    last_failed_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    locked_until TIMESTAMPTZ
+);
@@ -0,0 +1,11 @@
+// Package schema embeds the database migrations.
+//
+// Each file is named NNN_description.sql and holds a "-- +goose Up" section
+// and a "-- +goose Down" section. Up sections are written to be idempotent
+// so databases created before versioning existed can adopt it.
+package schema
+
+import "embed"
+
+//go:embed *.sql
+var FS embed.FS
@@ -0,0 +1,172 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/natrayanp/GoMicro/auth-service/internal/core"
+	"github.com/natrayanp/GoMicro/auth-service/internal/domain"
+	"github.com/natrayanp/GoMicro/auth-service/internal/storage/memory"
+	"github.com/natrayanp/GoMicro/auth-service/internal/tokencache"
+	pb "github.com/natrayanp/GoMicro/auth-service/proto/auth/v1"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestAuthService_RevokeAccessToken(t *testing.T) {
+	service := newAuthServiceFixture(nil, withTestDenylist()).service
+	ctx := context.Background()
+
+	_, err := service.Register(ctx, "test@example.com", "password123")
+	require.NoError(t, err)
+	first, err := service.Login(ctx, "test@example.com", "password123")
+	require.NoError(t, err)
+	second, err := service.Login(ctx, "test@example.com", "password123")
+	require.NoError(t, err)
+
+	require.NoError(t, service.RevokeAccessToken(ctx, first.AccessToken))
+
+	_, err = service.ValidateToken(ctx, first.AccessToken)
+	assert.ErrorIs(t, err, domain.ErrTokenRevoked)
+
+	// Only the revoked token is affected
+	_, err = service.ValidateToken(ctx, second.AccessToken)
+	assert.NoError(t, err)
+
+	err = service.RevokeAccessToken(ctx, second.RefreshToken)
+	assert.ErrorIs(t, err, domain.ErrWrongTokenType)
+}
+
+func TestAuthService_RevokeAllUserTokensDeniesAccessTokens(t *testing.T) {
+	cache := tokencache.NewLRU(100, time.Minute)
+	service := newAuthServiceFixture(nil, core.WithValidationCache(cache), withTestDenylist()).service
+	ctx := context.Background()
+
+	user, err := service.Register(ctx, "test@example.com", "password123")
+	require.NoError(t, err)
+	tokens, err := service.Login(ctx, "test@example.com", "password123")
+	require.NoError(t, err)
+
+	// Cached first, so the revocation must also reach the cache
+	_, err = service.ValidateToken(ctx, tokens.AccessToken)
+	require.NoError(t, err)
+
+	require.NoError(t, service.RevokeAllUserTokens(ctx, user.ID))
+
+	_, err = service.ValidateToken(ctx, tokens.AccessToken)
+	assert.ErrorIs(t, err, domain.ErrTokenRevoked)
+}
+
+func TestAuthService_LoginAfterRevocationValidates(t *testing.T) {
+	f := newAuthServiceFixture(nil, withTestDenylist())
+	ctx := context.Background()
+
+	user, err := f.service.Register(ctx, "test@example.com", "password123")
+	require.NoError(t, err)
+
+	// Logging in again straight after revoking everything, within the same
+	// second, gives a token the revocation does not cover
+	require.NoError(t, f.service.RevokeAllUserTokens(ctx, user.ID))
+	tokens, err := f.service.Login(ctx, "test@example.com", "password123")
+	require.NoError(t, err)
+	_, err = f.service.ValidateToken(ctx, tokens.AccessToken)
+	assert.NoError(t, err)
+
+	require.NoError(t, f.service.UpdateUserPassword(ctx, user.ID, "new-password456"))
+	_, err = f.service.ValidateToken(ctx, tokens.AccessToken)
+	assert.ErrorIs(t, err, domain.ErrTokenRevoked)
+
+	tokens, err = f.service.Login(ctx, "test@example.com", "new-password456")
+	require.NoError(t, err)
+	_, err = f.service.ValidateToken(ctx, tokens.AccessToken)
+	assert.NoError(t, err)
+}
+
+func TestAuthService_CachedTokensSeeRemoteRevocationsAfterTTL(t *testing.T) {
+	// The denylist is shared by every instance; the cache is not
+	denylist := memory.NewAccessTokenDenylistRepository()
+	service := newAuthServiceFixture(nil,
+		core.WithValidationCache(tokencache.NewLRU(100, 50*time.Millisecond)),
+		core.WithAccessTokenDenylist(denylist, 15*time.Minute),
+	).service
+	ctx := context.Background()
+
+	_, err := service.Register(ctx, "test@example.com", "password123")
+	require.NoError(t, err)
+	tokens, err := service.Login(ctx, "test@example.com", "password123")
+	require.NoError(t, err)
+
+	claims, err := service.ValidateToken(ctx, tokens.AccessToken)
+	require.NoError(t, err)
+
+	// Revoked elsewhere, so this instance's cache never heard of it and
+	// keeps the token valid until the entry expires
+	require.NoError(t, denylist.RevokeAccessToken(ctx, claims.ID, claims.Subject, claims.ExpiresAt))
+
+	_, err = service.ValidateToken(ctx, tokens.AccessToken)
+	assert.NoError(t, err)
+
+	time.Sleep(60 * time.Millisecond)
+	_, err = service.ValidateToken(ctx, tokens.AccessToken)
+	assert.ErrorIs(t, err, domain.ErrTokenRevoked)
+}
+
+func TestAuthService_RefreshTokenReuseDeniesAccessTokens(t *testing.T) {
+	service := newAuthServiceFixture(nil, withTestDenylist()).service
+	ctx := context.Background()
+
+	_, err := service.Register(ctx, "test@example.com", "password123")
+	require.NoError(t, err)
+	tokens, err := service.Login(ctx, "test@example.com", "password123")
+	require.NoError(t, err)
+
+	_, err = service.RefreshToken(ctx, tokens.RefreshToken)
+	require.NoError(t, err)
+	_, err = service.RefreshToken(ctx, tokens.RefreshToken)
+	require.ErrorIs(t, err, domain.ErrRefreshTokenReused)
+
+	_, err = service.ValidateToken(ctx, tokens.AccessToken)
+	assert.ErrorIs(t, err, domain.ErrTokenRevoked)
+}
+
+func TestGrpcServer_LogoutRevokesAccessToken(t *testing.T) {
+	client := startGrpcServer(t, newAuthServiceFixture(nil, withTestDenylist()).service)
+	ctx := context.Background()
+
+	user := registerAndLogin(t, client, "test@example.com")
+
+	resp, err := client.Validate(ctx, &pb.ValidateRequest{Token: user.tokens.AccessToken})
+	require.NoError(t, err)
+	require.True(t, resp.Valid)
+
+	_, err = client.Logout(ctx, &pb.LogoutRequest{
+		RefreshToken: user.tokens.RefreshToken,
+		AccessToken:  user.tokens.AccessToken,
+	})
+	require.NoError(t, err)
+
+	resp, err = client.Validate(ctx, &pb.ValidateRequest{Token: user.tokens.AccessToken})
+	require.NoError(t, err)
+	assert.False(t, resp.Valid)
+}
+
+func TestGrpcServer_LogoutWithBadRefreshTokenRevokesAccessToken(t *testing.T) {
+	client := startGrpcServer(t, newAuthServiceFixture(nil, withTestDenylist()).service)
+	ctx := context.Background()
+
+	user := registerAndLogin(t, client, "test@example.com")
+
+	_, err := client.Logout(ctx, &pb.LogoutRequest{
+		RefreshToken: "not-a-refresh-token",
+		AccessToken:  user.tokens.AccessToken,
+	})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	resp, err := client.Validate(ctx, &pb.ValidateRequest{Token: user.tokens.AccessToken})
+	require.NoError(t, err)
+	assert.False(t, resp.Valid)
+}
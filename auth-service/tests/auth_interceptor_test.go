@@ -0,0 +1,146 @@
+package tests
+
+import (
+	"context"
+	"testing"
+
+	grpcadapter "github.com/natrayanp/GoMicro/auth-service/internal/adapters/grpc"
+	"github.com/natrayanp/GoMicro/auth-service/internal/config"
+	"github.com/natrayanp/GoMicro/auth-service/internal/domain"
+	pb "github.com/natrayanp/GoMicro/auth-service/proto/auth/v1"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/reflection"
+	reflectionv1 "google.golang.org/grpc/reflection/grpc_reflection_v1"
+	"google.golang.org/grpc/status"
+)
+
+func TestAuthInterceptor_GetUserIsSelfOnly(t *testing.T) {
+	client := startGrpcServer(t, newAuthServiceFixture(nil).service)
+	alice := registerAndLogin(t, client, "alice@example.com")
+	bob := registerAndLogin(t, client, "bob@example.com")
+
+	_, err := client.GetUser(context.Background(), &pb.GetUserRequest{UserId: alice.id})
+	assertReason(t, err, codes.Unauthenticated, grpcadapter.ReasonMissingToken)
+
+	_, err = client.GetUser(withBearer("not-a-token"), &pb.GetUserRequest{UserId: alice.id})
+	assertReason(t, err, codes.Unauthenticated, domain.ReasonInvalidToken)
+
+	// A refresh token is not an access token
+	_, err = client.GetUser(withBearer(alice.tokens.RefreshToken), &pb.GetUserRequest{UserId: alice.id})
+	assertReason(t, err, codes.Unauthenticated, domain.ReasonWrongTokenType)
+
+	resp, err := client.GetUser(withBearer(alice.tokens.AccessToken), &pb.GetUserRequest{UserId: alice.id})
+	require.NoError(t, err)
+	assert.Equal(t, "alice@example.com", resp.User.Email)
+
+	_, err = client.GetUser(withBearer(bob.tokens.AccessToken), &pb.GetUserRequest{UserId: alice.id})
+	assertReason(t, err, codes.PermissionDenied, grpcadapter.ReasonPermissionDenied)
+}
+
+func TestAuthInterceptor_AdminsReadAnyUser(t *testing.T) {
+	service := newAuthServiceFixture(nil).service
+	admin, err := service.Register(context.Background(), "admin@example.com", "password123")
+	require.NoError(t, err)
+
+	cfg := &config.Config{Auth: config.AuthConfig{AdminUserIDs: []string{admin.ID}}}
+	client := startGrpcServerWithConfig(t, cfg, service)
+
+	adminLogin, err := client.Login(context.Background(), &pb.LoginRequest{Email: "admin@example.com", Password: "password123"})
+	require.NoError(t, err)
+	alice := registerAndLogin(t, client, "alice@example.com")
+
+	resp, err := client.GetUser(withBearer(adminLogin.AccessToken), &pb.GetUserRequest{UserId: alice.id})
+	require.NoError(t, err)
+	assert.Equal(t, alice.id, resp.User.Id)
+}
+
+func TestAuthInterceptor_MethodPolicyOverride(t *testing.T) {
+	client := startGrpcServer(t, newAuthServiceFixture(nil).service, grpcadapter.WithMethodPolicy(grpcadapter.MethodPolicy{
+		pb.AuthService_GetUser_FullMethodName: grpcadapter.AccessAuthenticated,
+	}))
+	alice := registerAndLogin(t, client, "alice@example.com")
+	bob := registerAndLogin(t, client, "bob@example.com")
+
+	_, err := client.GetUser(withBearer(bob.tokens.AccessToken), &pb.GetUserRequest{UserId: alice.id})
+	assert.NoError(t, err)
+
+	// Public methods still need no token
+	_, err = client.Refresh(context.Background(), &pb.RefreshRequest{RefreshToken: alice.tokens.RefreshToken})
+	assert.NoError(t, err)
+}
+
+// listServices asks server reflection for the registered services
+func listServices(ctx context.Context, conn *grpc.ClientConn) ([]string, error) {
+	stream, err := reflectionv1.NewServerReflectionClient(conn).ServerReflectionInfo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer stream.CloseSend()
+
+	err = stream.Send(&reflectionv1.ServerReflectionRequest{
+		MessageRequest: &reflectionv1.ServerReflectionRequest_ListServices{},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := stream.Recv()
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, service := range resp.GetListServicesResponse().GetService() {
+		names = append(names, service.Name)
+	}
+	return names, nil
+}
+
+func TestAuthInterceptor_ReflectionIsOptIn(t *testing.T) {
+	handler := grpcadapter.NewGrpcAuthHandler(newAuthServiceFixture(nil).service)
+
+	disabled := serveBufconn(t, grpcadapter.NewGrpcServer(&config.Config{}, handler))
+	_, err := listServices(context.Background(), disabled)
+	assert.Equal(t, codes.Unimplemented, status.Code(err))
+
+	cfg := &config.Config{Server: config.ServerConfig{Reflection: true}}
+	server := grpcadapter.NewGrpcServer(cfg, handler)
+	server.RegisterService()
+
+	// Enabled reflection is public
+	names, err := listServices(context.Background(), serveBufconn(t, server))
+	require.NoError(t, err)
+	assert.Contains(t, names, pb.AuthService_ServiceDesc.ServiceName)
+}
+
+func TestAuthInterceptor_StreamsFollowPolicy(t *testing.T) {
+	service := newAuthServiceFixture(nil).service
+	admin, err := service.Register(context.Background(), "admin@example.com", "password123")
+	require.NoError(t, err)
+
+	// Registered outside the config, reflection is not in the policy and so
+	// is admin-only
+	cfg := &config.Config{Auth: config.AuthConfig{AdminUserIDs: []string{admin.ID}}}
+	server := grpcadapter.NewGrpcServer(cfg, grpcadapter.NewGrpcAuthHandler(service))
+	server.RegisterService()
+	reflection.Register(server.GrpcServer())
+
+	conn := serveBufconn(t, server)
+	client := pb.NewAuthServiceClient(conn)
+	alice := registerAndLogin(t, client, "alice@example.com")
+	adminLogin, err := client.Login(context.Background(), &pb.LoginRequest{Email: "admin@example.com", Password: "password123"})
+	require.NoError(t, err)
+
+	_, err = listServices(context.Background(), conn)
+	assertReason(t, err, codes.Unauthenticated, grpcadapter.ReasonMissingToken)
+
+	_, err = listServices(withBearer(alice.tokens.AccessToken), conn)
+	assertReason(t, err, codes.PermissionDenied, grpcadapter.ReasonPermissionDenied)
+
+	_, err = listServices(withBearer(adminLogin.AccessToken), conn)
+	assert.NoError(t, err)
+}
@@ -1,88 +1,253 @@
 package tests
 
 import (
+	"context"
+	"errors"
+	"sync"
 	"testing"
 	"time"
 
-	"github.com/natrayanp/GoMicro/auth-service/internal/auth/jwt"
-	"github.com/natrayanp/GoMicro/auth-service/internal/core"
 	"github.com/natrayanp/GoMicro/auth-service/internal/domain"
+	"github.com/natrayanp/GoMicro/auth-service/internal/ports"
+	"github.com/natrayanp/GoMicro/auth-service/internal/storage/memory"
 
 	"github.com/stretchr/testify/assert"
-	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
-// Mock repositories
-type MockUserRepository struct {
-	mock.Mock
-}
+func TestAuthService_Register(t *testing.T) {
+	f := newAuthServiceFixture(nil)
+	ctx := context.Background()
 
-func (m *MockUserRepository) Create(user *domain.User) error {
-	args := m.Called(user)
-	return args.Error(0)
-}
+	user, err := f.service.Register(ctx, "test@example.com", "password123")
 
-func (m *MockUserRepository) FindByEmail(email string) (*domain.User, error) {
-	args := m.Called(email)
-	return args.Get(0).(*domain.User), args.Error(1)
+	assert.NoError(t, err)
+	assert.NotNil(t, user)
+	assert.NotEmpty(t, user.ID)
+	assert.Equal(t, "test@example.com", user.Email)
+	assert.NotEqual(t, "password123", user.PasswordHash)
+
+	_, err = f.service.Register(ctx, "test@example.com", "password123")
+	assert.ErrorIs(t, err, domain.ErrUserExists)
 }
 
-func (m *MockUserRepository) FindByID(id string) (*domain.User, error) {
-	args := m.Called(id)
-	return args.Get(0).(*domain.User), args.Error(1)
+func TestAuthService_LoginAndValidate(t *testing.T) {
+	f := newAuthServiceFixture(nil)
+	ctx := context.Background()
+
+	user, tokenPair := f.login(t)
+
+	claims, err := f.service.ValidateToken(ctx, tokenPair.AccessToken)
+	require.NoError(t, err)
+	assert.Equal(t, user.ID, claims.Subject)
+
+	// Refresh tokens are not access tokens
+	_, err = f.service.ValidateToken(ctx, tokenPair.RefreshToken)
+	assert.ErrorIs(t, err, domain.ErrWrongTokenType)
+
+	_, err = f.service.Login(ctx, "test@example.com", "wrong-password")
+	assert.ErrorIs(t, err, domain.ErrInvalidCredentials)
+
+	_, err = f.service.Login(ctx, "nobody@example.com", "password123")
+	assert.ErrorIs(t, err, domain.ErrInvalidCredentials)
 }
 
-func (m *MockUserRepository) Update(user *domain.User) error {
-	args := m.Called(user)
-	return args.Error(0)
+func TestAuthService_RefreshTokenRotates(t *testing.T) {
+	for name, provider := range map[string]ports.TokenProviderPort{
+		"jwt":    nil,
+		"opaque": newOpaqueProvider(),
+	} {
+		t.Run(name, func(t *testing.T) {
+			f := newAuthServiceFixture(provider)
+			ctx := context.Background()
+
+			user, tokenPair := f.login(t)
+
+			rotated, err := f.service.RefreshToken(ctx, tokenPair.RefreshToken)
+			assert.NoError(t, err)
+			assert.NotEqual(t, tokenPair.RefreshToken, rotated.RefreshToken)
+
+			// Only the new token is still live
+			valid, err := f.tokens.GetValidRefreshTokens(ctx, user.ID)
+			assert.NoError(t, err)
+			assert.Len(t, valid, 1)
+
+			_, err = f.service.RefreshToken(ctx, rotated.RefreshToken)
+			assert.NoError(t, err)
+		})
+	}
 }
 
-func (m *MockUserRepository) Delete(id string) error {
-	args := m.Called(id)
-	return args.Error(0)
+func TestAuthService_RefreshTokenReuseRevokesFamily(t *testing.T) {
+	f := newAuthServiceFixture(nil)
+	ctx := context.Background()
+
+	user, tokenPair := f.login(t)
+
+	rotated, err := f.service.RefreshToken(ctx, tokenPair.RefreshToken)
+	assert.NoError(t, err)
+
+	// Presenting the rotated token again ends the whole session
+	_, err = f.service.RefreshToken(ctx, tokenPair.RefreshToken)
+	assert.ErrorIs(t, err, domain.ErrRefreshTokenReused)
+
+	_, err = f.service.RefreshToken(ctx, rotated.RefreshToken)
+	assert.ErrorIs(t, err, domain.ErrTokenRevoked)
+
+	valid, err := f.tokens.GetValidRefreshTokens(ctx, user.ID)
+	assert.NoError(t, err)
+	assert.Empty(t, valid)
+
+	assert.Eventually(t, func() bool {
+		for _, event := range f.publisher.Events() {
+			if event.Name == domain.EventRefreshTokenReused {
+				return event.Data.(domain.RefreshTokenReusedEvent).UserID == user.ID
+			}
+		}
+		return false
+	}, time.Second, 10*time.Millisecond)
 }
 
-type MockTokenRepository struct {
-	mock.Mock
+func TestAuthService_ConcurrentRefreshOnlyOneWins(t *testing.T) {
+	f := newAuthServiceFixture(nil)
+	ctx := context.Background()
+
+	_, tokenPair := f.login(t)
+
+	const callers = 8
+	var (
+		wg        sync.WaitGroup
+		mu        sync.Mutex
+		succeeded int
+	)
+
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := f.service.RefreshToken(ctx, tokenPair.RefreshToken); err == nil {
+				mu.Lock()
+				succeeded++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, 1, succeeded)
 }
 
-func (m *MockTokenRepository) CreateRefreshToken(userID, tokenHash string, expiresAt time.Time) error {
-	args := m.Called(userID, tokenHash, expiresAt)
-	return args.Error(0)
+// racingTokenRepository holds every lookup until n callers have read the
+// token, so they all see it live before any of them rotates it
+type racingTokenRepository struct {
+	*memory.TokenRepository
+	read *sync.WaitGroup
 }
 
-func (m *MockTokenRepository) GetRefreshToken(tokenHash string) (*domain.RefreshToken, error) {
-	args := m.Called(tokenHash)
-	return args.Get(0).(*domain.RefreshToken), args.Error(1)
+func (r racingTokenRepository) GetRefreshToken(ctx context.Context, tokenHash string) (*domain.RefreshToken, error) {
+	token, err := r.TokenRepository.GetRefreshToken(ctx, tokenHash)
+	r.read.Done()
+	r.read.Wait()
+	return token, err
 }
 
-func (m *MockTokenRepository) RevokeRefreshToken(tokenHash string) error {
-	args := m.Called(tokenHash)
-	return args.Error(0)
+func TestAuthService_LostRefreshRaceIsNotReuse(t *testing.T) {
+	const callers = 2
+	read := &sync.WaitGroup{}
+	f := newAuthServiceFixtureOn(ports.TxRepositories{
+		Tokens: racingTokenRepository{TokenRepository: memory.NewTokenRepository(), read: read},
+	}, nil)
+	service := f.service
+	ctx := context.Background()
+
+	_, err := service.Register(ctx, "test@example.com", "password123")
+	require.NoError(t, err)
+	tokenPair, err := service.Login(ctx, "test@example.com", "password123")
+	require.NoError(t, err)
+
+	read.Add(callers)
+	results := make(chan error, callers)
+	winners := make(chan *domain.TokenPair, callers)
+	for i := 0; i < callers; i++ {
+		go func() {
+			next, err := service.RefreshToken(ctx, tokenPair.RefreshToken)
+			if err == nil {
+				winners <- next
+			}
+			results <- err
+		}()
+	}
+
+	var lost []error
+	for i := 0; i < callers; i++ {
+		if err := <-results; err != nil {
+			lost = append(lost, err)
+		}
+	}
+	require.Len(t, lost, callers-1)
+	assert.ErrorIs(t, lost[0], domain.ErrTokenRevoked)
+
+	// The winner's new token is untouched and nothing was reported
+	read.Add(1)
+	_, err = service.RefreshToken(ctx, (<-winners).RefreshToken)
+	assert.NoError(t, err)
+	for _, event := range f.publisher.Events() {
+		assert.NotEqual(t, domain.EventRefreshTokenReused, event.Name)
+	}
 }
 
-func (m *MockTokenRepository) RevokeAllUserTokens(userID string) error {
-	args := m.Called(userID)
-	return args.Error(0)
+func TestAuthService_RevokeToken(t *testing.T) {
+	f := newAuthServiceFixture(nil)
+	ctx := context.Background()
+
+	_, tokenPair := f.login(t)
+
+	assert.NoError(t, f.service.RevokeToken(ctx, tokenPair.RefreshToken))
+
+	_, err := f.service.RefreshToken(ctx, tokenPair.RefreshToken)
+	assert.ErrorIs(t, err, domain.ErrTokenRevoked)
 }
 
-func TestAuthService_Register(t *testing.T) {
-	mockUserRepo := new(MockUserRepository)
-	mockTokenRepo := new(MockTokenRepository)
+func TestAuthService_UpdateUserPasswordRevokesTokens(t *testing.T) {
+	f := newAuthServiceFixture(nil)
+	ctx := context.Background()
 
-	jwtProvider := jwt.NewProvider("test-secret", 15*time.Minute, 7*24*time.Hour)
+	user, tokenPair := f.login(t)
 
-	authService := core.NewAuthService(mockUserRepo, mockTokenRepo, jwtProvider)
+	assert.NoError(t, f.service.UpdateUserPassword(ctx, user.ID, "new-password123"))
 
-	// Test registration
-	mockUserRepo.On("FindByEmail", "test@example.com").Return((*domain.User)(nil), domain.ErrUserNotFound)
-	mockUserRepo.On("Create", mock.AnythingOfType("*domain.User")).Return(nil)
+	_, err := f.service.RefreshToken(ctx, tokenPair.RefreshToken)
+	assert.ErrorIs(t, err, domain.ErrTokenRevoked)
 
-	user, err := authService.Register("test@example.com", "password123")
+	_, err = f.service.Login(ctx, "test@example.com", "password123")
+	assert.ErrorIs(t, err, domain.ErrInvalidCredentials)
 
+	_, err = f.service.Login(ctx, "test@example.com", "new-password123")
 	assert.NoError(t, err)
-	assert.NotNil(t, user)
-	assert.Equal(t, "test@example.com", user.Email)
+}
 
-	mockUserRepo.AssertExpectations(t)
+// failingTokenRepository fails to revoke, to exercise unit of work rollback
+type failingTokenRepository struct {
+	*memory.TokenRepository
+}
+
+func (r failingTokenRepository) RevokeAllUserTokens(ctx context.Context, userID string) error {
+	return errors.New("revoke failed")
+}
+
+func TestAuthService_UpdateUserPasswordRollsBack(t *testing.T) {
+	service := newAuthServiceFixtureOn(ports.TxRepositories{
+		Tokens: failingTokenRepository{memory.NewTokenRepository()},
+	}, nil).service
+	ctx := context.Background()
+
+	user, err := service.Register(ctx, "test@example.com", "password123")
+	assert.NoError(t, err)
+
+	err = service.UpdateUserPassword(ctx, user.ID, "new-password123")
+	assert.Error(t, err)
+
+	// The new hash was rolled back together with the failed revoke
+	_, err = service.Login(ctx, "test@example.com", "password123")
+	assert.NoError(t, err)
 }
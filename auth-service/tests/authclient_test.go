@@ -0,0 +1,482 @@
+package tests
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"fmt"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	grpcadapter "github.com/natrayanp/GoMicro/auth-service/internal/adapters/grpc"
+	"github.com/natrayanp/GoMicro/auth-service/internal/auth/jwt"
+	"github.com/natrayanp/GoMicro/auth-service/internal/config"
+	"github.com/natrayanp/GoMicro/auth-service/internal/core"
+	"github.com/natrayanp/GoMicro/auth-service/internal/domain"
+	"github.com/natrayanp/GoMicro/auth-service/internal/storage/memory"
+	"github.com/natrayanp/GoMicro/auth-service/pkg/authclient"
+	pb "github.com/natrayanp/GoMicro/auth-service/proto/auth/v1"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+// startAuthClient serves the service over an in-memory connection and
+// returns an SDK client for it
+func startAuthClient(t *testing.T, service *core.AuthService, opts ...authclient.Option) *authclient.Client {
+	t.Helper()
+
+	server := grpcadapter.NewGrpcServer(&config.Config{}, grpcadapter.NewGrpcAuthHandler(service))
+	server.RegisterService()
+
+	return authclient.New(serveBufconn(t, server), opts...)
+}
+
+// countingAuthServer fails Validate with Unavailable a set number of times
+// and counts every call
+type countingAuthServer struct {
+	pb.UnimplementedAuthServiceServer
+	failures atomic.Int32
+	validate atomic.Int32
+	refresh  atomic.Int32
+}
+
+func (s *countingAuthServer) Validate(ctx context.Context, req *pb.ValidateRequest) (*pb.ValidateResponse, error) {
+	s.validate.Add(1)
+	if s.failures.Add(-1) >= 0 {
+		return nil, status.Error(codes.Unavailable, "try again")
+	}
+	return &pb.ValidateResponse{Valid: req.Token == "good", UserId: "user-1"}, nil
+}
+
+func (s *countingAuthServer) Refresh(context.Context, *pb.RefreshRequest) (*pb.RefreshResponse, error) {
+	s.refresh.Add(1)
+	return nil, status.Error(codes.Unavailable, "try again")
+}
+
+func startCountingClient(t *testing.T, failures int32, opts ...authclient.Option) (*authclient.Client, *countingAuthServer) {
+	t.Helper()
+
+	fake := &countingAuthServer{}
+	fake.failures.Store(failures)
+
+	server := grpcadapter.NewGrpcServer(&config.Config{}, grpcadapter.NewGrpcAuthHandler(nil))
+	pb.RegisterAuthServiceServer(server.GrpcServer(), fake)
+
+	return authclient.New(serveBufconn(t, server), opts...), fake
+}
+
+func TestAuthClient_SessionRefreshesAccessToken(t *testing.T) {
+	client := startAuthClient(t, newAuthServiceFixture(nil).service)
+	ctx := context.Background()
+
+	userID, err := client.Register(ctx, "alice@example.com", "password123")
+	require.NoError(t, err)
+
+	tokens, err := client.Login(ctx, "alice@example.com", "password123")
+	require.NoError(t, err)
+	assert.Equal(t, 15*time.Minute, tokens.ExpiresIn)
+
+	user, err := client.GetUser(ctx, tokens.AccessToken, userID)
+	require.NoError(t, err)
+	assert.Equal(t, "alice@example.com", user.Email)
+
+	session := client.NewSession(tokens)
+	token, err := session.AccessToken(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, tokens.AccessToken, token, "fresh token is reused")
+
+	// Pretend the access token is about to expire
+	almostExpired := *tokens
+	almostExpired.ExpiresAt = time.Now().Add(time.Second)
+	session = client.NewSession(&almostExpired)
+
+	token, err = session.AccessToken(ctx)
+	require.NoError(t, err)
+	assert.NotEqual(t, tokens.AccessToken, token)
+	assert.NotEqual(t, tokens.RefreshToken, session.Tokens().RefreshToken)
+
+	claims, err := client.Validate(ctx, token)
+	require.NoError(t, err)
+	assert.Equal(t, userID, claims.UserID)
+	assert.NotEmpty(t, claims.SessionID)
+
+	require.NoError(t, session.Logout(ctx))
+	_, err = client.Refresh(ctx, session.Tokens().RefreshToken)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	assert.Equal(t, "TOKEN_REVOKED", authclient.Reason(err))
+}
+
+func TestAuthClient_RetriesOnlyIdempotentCalls(t *testing.T) {
+	client, fake := startCountingClient(t, 2, authclient.WithRetries(3, time.Millisecond))
+	ctx := context.Background()
+
+	claims, err := client.Validate(ctx, "good")
+	require.NoError(t, err)
+	assert.Equal(t, "user-1", claims.UserID)
+	assert.Equal(t, int32(3), fake.validate.Load())
+
+	// Reusing a refresh token ends the session, so Refresh never retries
+	_, err = client.Refresh(ctx, "refresh")
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+	assert.Equal(t, int32(1), fake.refresh.Load())
+}
+
+func TestAuthClient_NormalizesRetryOptions(t *testing.T) {
+	tests := []struct {
+		name      string
+		opts      []authclient.Option
+		failures  int32
+		wantCode  codes.Code
+		wantCalls int32
+	}{
+		{"zero backoff", []authclient.Option{authclient.WithRetries(3, 0)}, 2, codes.OK, 3},
+		{"negative backoff", []authclient.Option{authclient.WithRetries(3, -time.Second)}, 2, codes.OK, 3},
+		{"overflowing backoff", []authclient.Option{authclient.WithRetries(64, math.MaxInt64)}, 64, codes.Unavailable, 1},
+		{"zero attempts", []authclient.Option{authclient.WithRetries(0, time.Millisecond)}, 2, codes.Unavailable, 1},
+		{"zero timeout", []authclient.Option{authclient.WithTimeout(0)}, 0, codes.OK, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, fake := startCountingClient(t, tt.failures, tt.opts...)
+			// The overflowing backoff is capped, not skipped; give up
+			// before the first wait ends
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+
+			_, err := client.Validate(ctx, "good")
+			assert.Equal(t, tt.wantCode, status.Code(err))
+			assert.Equal(t, tt.wantCalls, fake.validate.Load())
+		})
+	}
+}
+
+func TestAuthClient_RetryDelay(t *testing.T) {
+	f := newAuthServiceFixture(nil, core.WithLockout(memory.NewLoginAttemptRepository(), domain.LockoutPolicy{
+		MaxAttempts:  2,
+		BaseDuration: time.Minute,
+		MaxDuration:  time.Hour,
+		ResetAfter:   time.Hour,
+	}))
+	client := startAuthClient(t, f.service)
+	ctx := context.Background()
+
+	_, err := client.Register(ctx, "alice@example.com", "password123")
+	require.NoError(t, err)
+
+	for i := 0; i < 2; i++ {
+		_, err = client.Login(ctx, "alice@example.com", "wrong-password")
+	}
+
+	assert.Equal(t, "ACCOUNT_LOCKED", authclient.Reason(err))
+	delay, ok := authclient.RetryDelay(err)
+	assert.True(t, ok)
+	assert.Greater(t, delay, time.Duration(0))
+}
+
+func TestHMACVerifier(t *testing.T) {
+	provider := jwt.NewJWTProvider("test-secret", 15*time.Minute, 7*24*time.Hour)
+	verifier := authclient.NewHMACVerifier("test-secret")
+	ctx := context.Background()
+
+	access, err := provider.GenerateAccessToken("user-1")
+	require.NoError(t, err)
+
+	claims, err := verifier.Verify(ctx, access)
+	require.NoError(t, err)
+	assert.Equal(t, "user-1", claims.UserID)
+	assert.NotEmpty(t, claims.TokenID)
+
+	refresh, err := provider.GenerateRefreshToken("user-1")
+	require.NoError(t, err)
+	_, err = verifier.Verify(ctx, refresh)
+	assert.ErrorIs(t, err, authclient.ErrInvalidToken)
+
+	_, err = authclient.NewHMACVerifier("other-secret").Verify(ctx, access)
+	assert.ErrorIs(t, err, authclient.ErrInvalidToken)
+
+	_, err = authclient.NewHMACVerifier("test-secret", authclient.WithAudience("billing")).Verify(ctx, access)
+	assert.ErrorIs(t, err, authclient.ErrInvalidToken)
+
+	expired, err := jwt.NewJWTProvider("test-secret", -time.Minute, time.Hour).GenerateAccessToken("user-1")
+	require.NoError(t, err)
+	_, err = verifier.Verify(ctx, expired)
+	assert.ErrorIs(t, err, authclient.ErrTokenExpired)
+
+	_, err = verifier.Verify(ctx, "")
+	assert.ErrorIs(t, err, authclient.ErrMissingToken)
+
+	granted, err := provider.GenerateAccessTokenFor(domain.TokenGrant{
+		UserID:    "user-1",
+		SessionID: "session-1",
+		Roles:     []string{domain.RoleAdmin},
+		Scopes:    []string{"orders:read"},
+	})
+	require.NoError(t, err)
+	claims, err = verifier.Verify(ctx, granted)
+	require.NoError(t, err)
+	assert.Equal(t, "session-1", claims.SessionID)
+	assert.True(t, claims.HasRole(domain.RoleAdmin))
+	assert.True(t, claims.HasScope("orders:read"))
+	assert.False(t, claims.HasScope("orders:write"))
+}
+
+func TestJWKSVerifier(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	tests := []struct {
+		method string
+		key    any
+	}{
+		{"RS256", rsaKey},
+		{"ES256", ecKey},
+		{"EdDSA", edKey},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.method, func(t *testing.T) {
+			provider, err := jwt.NewProviderFromConfig(&config.JWTConfig{
+				SigningMethod:  tt.method,
+				PrivateKeyFile: writePrivateKeyPEM(t, tt.key),
+				AccessExpiry:   15 * time.Minute,
+				RefreshExpiry:  7 * 24 * time.Hour,
+			})
+			require.NoError(t, err)
+
+			jwks := httptest.NewServer(provider.JWKSHandler())
+			defer jwks.Close()
+
+			verifier := authclient.NewJWKSVerifier(jwks.URL)
+			access, err := provider.GenerateAccessToken("user-1")
+			require.NoError(t, err)
+
+			claims, err := verifier.Verify(context.Background(), access)
+			require.NoError(t, err)
+			assert.Equal(t, "user-1", claims.UserID)
+
+			// Shared-secret tokens are never accepted by a JWKS verifier
+			forged, err := jwt.NewJWTProvider("guess", time.Minute, time.Hour).GenerateAccessToken("user-1")
+			require.NoError(t, err)
+			_, err = verifier.Verify(context.Background(), forged)
+			assert.ErrorIs(t, err, authclient.ErrInvalidToken)
+		})
+	}
+}
+
+func TestJWKSVerifier_Unavailable(t *testing.T) {
+	jwks := httptest.NewServer(http.NotFoundHandler())
+	jwks.Close()
+
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	provider, err := jwt.NewProviderFromConfig(&config.JWTConfig{
+		SigningMethod:  "EdDSA",
+		PrivateKeyFile: writePrivateKeyPEM(t, edKey),
+		AccessExpiry:   15 * time.Minute,
+		RefreshExpiry:  time.Hour,
+	})
+	require.NoError(t, err)
+	access, err := provider.GenerateAccessToken("user-1")
+	require.NoError(t, err)
+
+	_, err = authclient.NewJWKSVerifier(jwks.URL).Verify(context.Background(), access)
+	assert.ErrorIs(t, err, authclient.ErrUnavailable)
+}
+
+func TestJWKSVerifier_FetchesOutsideLookups(t *testing.T) {
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	provider, err := jwt.NewProviderFromConfig(&config.JWTConfig{
+		SigningMethod:  "EdDSA",
+		PrivateKeyFile: writePrivateKeyPEM(t, edKey),
+		AccessExpiry:   15 * time.Minute,
+		RefreshExpiry:  time.Hour,
+	})
+	require.NoError(t, err)
+	access, err := provider.GenerateAccessToken("user-1")
+	require.NoError(t, err)
+
+	// The endpoint answers the first fetch, then hangs until released
+	var fetches atomic.Int32
+	release := make(chan struct{})
+	jwks := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if fetches.Add(1) > 1 {
+			<-release
+		}
+		provider.JWKSHandler()(w, r)
+	}))
+	defer jwks.Close()
+	defer close(release)
+
+	verifier := authclient.NewJWKSVerifier(jwks.URL, authclient.WithJWKSRefresh(time.Millisecond))
+	ctx := context.Background()
+
+	// Concurrent first lookups share one fetch
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := verifier.Verify(ctx, access)
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, int32(1), fetches.Load())
+
+	// The document is stale and its refetch hangs, but the known key is
+	// still served without waiting for it
+	time.Sleep(5 * time.Millisecond)
+	for i := 0; i < 3; i++ {
+		start := time.Now()
+		_, err = verifier.Verify(ctx, access)
+		require.NoError(t, err)
+		assert.Less(t, time.Since(start), time.Second)
+	}
+	assert.Eventually(t, func() bool { return fetches.Load() == 2 }, time.Second, time.Millisecond)
+}
+
+func TestRemoteVerifier_CachesValidTokens(t *testing.T) {
+	client, fake := startCountingClient(t, 0)
+	verifier := authclient.NewRemoteVerifier(client)
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		claims, err := verifier.Verify(ctx, "good")
+		require.NoError(t, err)
+		assert.Equal(t, "user-1", claims.UserID)
+	}
+	assert.Equal(t, int32(1), fake.validate.Load())
+
+	_, err := verifier.Verify(ctx, "bad")
+	assert.ErrorIs(t, err, authclient.ErrInvalidToken)
+}
+
+func TestRemoteVerifier_ReportsRevocation(t *testing.T) {
+	client := startAuthClient(t, newAuthServiceFixture(nil, withTestDenylist()).service)
+	ctx := context.Background()
+
+	_, err := client.Register(ctx, "alice@example.com", "password123")
+	require.NoError(t, err)
+	tokens, err := client.Login(ctx, "alice@example.com", "password123")
+	require.NoError(t, err)
+
+	verifier := authclient.NewRemoteVerifier(client, authclient.WithCache(0, 0))
+	claims, err := verifier.Verify(ctx, tokens.AccessToken)
+	require.NoError(t, err)
+	assert.NotEmpty(t, claims.SessionID)
+	assert.WithinDuration(t, tokens.ExpiresAt, claims.ExpiresAt, 2*time.Second)
+
+	require.NoError(t, client.NewSession(tokens).Logout(ctx))
+
+	_, err = verifier.Verify(ctx, tokens.AccessToken)
+	assert.ErrorIs(t, err, authclient.ErrTokenRevoked)
+}
+
+func TestMiddleware_HTTP(t *testing.T) {
+	provider := jwt.NewJWTProvider("test-secret", 15*time.Minute, 7*24*time.Hour)
+	handler := authclient.Middleware(authclient.NewHMACVerifier("test-secret"))(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			userID, _ := authclient.UserIDFromContext(r.Context())
+			w.Write([]byte(userID))
+		}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	assert.Equal(t, "Bearer", rec.Header().Get("WWW-Authenticate"))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Authorization", "Bearer not-a-token")
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	assert.Contains(t, rec.Header().Get("WWW-Authenticate"), "invalid_token")
+	assert.Equal(t, "unauthorized\n", rec.Body.String(), "verifier errors stay out of the response")
+
+	access, err := provider.GenerateAccessToken("user-1")
+	require.NoError(t, err)
+	req = httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Authorization", "Bearer "+access)
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "user-1", rec.Body.String())
+}
+
+// unavailableVerifier fails every check the way a verifier does when the
+// auth service cannot be reached
+type unavailableVerifier struct{}
+
+func (unavailableVerifier) Verify(context.Context, string) (*authclient.Claims, error) {
+	return nil, fmt.Errorf("%w: dial tcp 10.0.0.7:50051: connection refused", authclient.ErrUnavailable)
+}
+
+func TestMiddleware_HTTPUnavailable(t *testing.T) {
+	handler := authclient.Middleware(unavailableVerifier{})(http.NotFoundHandler())
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Authorization", "Bearer token")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	assert.Equal(t, "service unavailable\n", rec.Body.String())
+}
+
+// userIDHealth reports SERVING only to callers the interceptor identified
+type userIDHealth struct {
+	grpc_health_v1.UnimplementedHealthServer
+}
+
+func (userIDHealth) Check(ctx context.Context, _ *grpc_health_v1.HealthCheckRequest) (*grpc_health_v1.HealthCheckResponse, error) {
+	if _, ok := authclient.UserIDFromContext(ctx); !ok {
+		return nil, status.Error(codes.Internal, "no user in context")
+	}
+	return &grpc_health_v1.HealthCheckResponse{Status: grpc_health_v1.HealthCheckResponse_SERVING}, nil
+}
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	provider := jwt.NewJWTProvider("test-secret", 15*time.Minute, 7*24*time.Hour)
+	verifier := authclient.NewHMACVerifier("test-secret")
+
+	downstream := grpc.NewServer(grpc.UnaryInterceptor(authclient.UnaryServerInterceptor(verifier)))
+	grpc_health_v1.RegisterHealthServer(downstream, userIDHealth{})
+
+	client := grpc_health_v1.NewHealthClient(serveGrpcServer(t, downstream))
+
+	_, err := client.Check(context.Background(), &grpc_health_v1.HealthCheckRequest{})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	access, err := provider.GenerateAccessToken("user-1")
+	require.NoError(t, err)
+	resp, err := client.Check(withBearer(access), &grpc_health_v1.HealthCheckRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, grpc_health_v1.HealthCheckResponse_SERVING, resp.Status)
+
+	// Public methods skip verification
+	public := grpc.NewServer(grpc.UnaryInterceptor(authclient.UnaryServerInterceptor(verifier,
+		authclient.WithPublicMethods("/grpc.health.v1.Health/Check"))))
+	grpc_health_v1.RegisterHealthServer(public, health.NewServer())
+
+	_, err = grpc_health_v1.NewHealthClient(serveGrpcServer(t, public)).Check(context.Background(), &grpc_health_v1.HealthCheckRequest{})
+	assert.NoError(t, err)
+}
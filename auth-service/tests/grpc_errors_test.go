@@ -0,0 +1,153 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	grpcadapter "github.com/natrayanp/GoMicro/auth-service/internal/adapters/grpc"
+	"github.com/natrayanp/GoMicro/auth-service/internal/domain"
+	"github.com/natrayanp/GoMicro/auth-service/internal/ports"
+	pb "github.com/natrayanp/GoMicro/auth-service/proto/auth/v1"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// failingService fails Login and ValidateToken with a fixed error; other
+// methods are not used
+type failingService struct {
+	ports.AuthServicePort
+	err error
+}
+
+func (s failingService) Login(ctx context.Context, email, password string) (*domain.TokenPair, error) {
+	return nil, s.err
+}
+
+func (s failingService) ValidateToken(ctx context.Context, token string) (*domain.TokenClaims, error) {
+	return nil, s.err
+}
+
+// loginError returns the status of a Login call that fails with err
+func loginError(t *testing.T, err error) *status.Status {
+	t.Helper()
+
+	client := startGrpcServer(t, failingService{err: err})
+	_, callErr := client.Login(context.Background(), &pb.LoginRequest{Email: "test@example.com", Password: "password123"})
+	require.Error(t, callErr)
+
+	return status.Convert(callErr)
+}
+
+func TestDomainError_WithMetadata(t *testing.T) {
+	err := domain.ErrTokenExpired.WithMetadata(map[string]string{"expired_at": "2024-01-01T00:00:00Z"})
+
+	assert.ErrorIs(t, err, domain.ErrTokenExpired)
+	assert.NotErrorIs(t, err, domain.ErrInvalidToken)
+	assert.Equal(t, domain.ReasonTokenExpired, err.Reason)
+	assert.Equal(t, "token expired", err.Error())
+	assert.Equal(t, "2024-01-01T00:00:00Z", err.Metadata["expired_at"])
+
+	// Deriving twice still unwraps to the sentinel, which stays untouched
+	again := err.WithMetadata(map[string]string{"user_id": "u1"})
+	assert.ErrorIs(t, again, domain.ErrTokenExpired)
+	assert.Len(t, again.Metadata, 2)
+	assert.Nil(t, domain.ErrTokenExpired.Metadata)
+}
+
+func TestGrpcErrors_MapsWrappedDomainErrors(t *testing.T) {
+	tests := []struct {
+		err    error
+		code   codes.Code
+		reason string
+	}{
+		{domain.ErrInvalidCredentials, codes.Unauthenticated, domain.ReasonInvalidCredentials},
+		{fmt.Errorf("load user: %w", domain.ErrUserNotFound), codes.NotFound, domain.ReasonUserNotFound},
+		{fmt.Errorf("create user: %w", domain.ErrUserExists), codes.AlreadyExists, domain.ReasonUserExists},
+		{fmt.Errorf("refresh: %w", domain.ErrTokenRevoked), codes.Unauthenticated, domain.ReasonTokenRevoked},
+		{domain.ErrRefreshTokenReused, codes.Unauthenticated, domain.ReasonRefreshTokenReused},
+		{domain.ErrWrongTokenType, codes.Unauthenticated, domain.ReasonWrongTokenType},
+	}
+
+	for _, tt := range tests {
+		st := loginError(t, tt.err)
+		assert.Equal(t, tt.code, st.Code(), tt.err.Error())
+
+		info, ok := statusDetail[*errdetails.ErrorInfo](st)
+		require.True(t, ok, tt.err.Error())
+		assert.Equal(t, tt.reason, info.Reason)
+		assert.Equal(t, grpcadapter.ErrorDomain, info.Domain)
+	}
+}
+
+func TestGrpcErrors_TokenExpiry(t *testing.T) {
+	err := domain.ErrTokenExpired.WithMetadata(map[string]string{"expired_at": "2024-01-01T00:00:00Z"})
+	st := loginError(t, fmt.Errorf("refresh: %w", err))
+
+	assert.Equal(t, codes.Unauthenticated, st.Code())
+	assert.Equal(t, "invalid token", st.Message())
+
+	info, ok := statusDetail[*errdetails.ErrorInfo](st)
+	require.True(t, ok)
+	assert.Equal(t, domain.ReasonTokenExpired, info.Reason)
+	assert.Equal(t, "2024-01-01T00:00:00Z", info.Metadata["expired_at"])
+}
+
+func TestGrpcErrors_FieldViolations(t *testing.T) {
+	st := loginError(t, domain.ErrInvalidEmail)
+	assert.Equal(t, codes.InvalidArgument, st.Code())
+
+	badRequest, ok := statusDetail[*errdetails.BadRequest](st)
+	require.True(t, ok)
+	require.Len(t, badRequest.FieldViolations, 1)
+	assert.Equal(t, "email", badRequest.FieldViolations[0].Field)
+	assert.Equal(t, domain.ReasonInvalidEmail, badRequest.FieldViolations[0].Reason)
+
+	policyErr := domain.NewPasswordPolicyError([]domain.PasswordViolation{
+		{Rule: domain.PasswordRuleMinLength, Message: "must be at least 12 characters"},
+		{Rule: domain.PasswordRuleDigit, Message: "must contain a digit"},
+	})
+	st = loginError(t, fmt.Errorf("register: %w", policyErr))
+	assert.Equal(t, codes.InvalidArgument, st.Code())
+	assert.Equal(t, "password does not meet policy", st.Message())
+
+	info, ok := statusDetail[*errdetails.ErrorInfo](st)
+	require.True(t, ok)
+	assert.Equal(t, domain.ReasonPasswordPolicy, info.Reason)
+
+	badRequest, ok = statusDetail[*errdetails.BadRequest](st)
+	require.True(t, ok)
+	require.Len(t, badRequest.FieldViolations, 2)
+	assert.Equal(t, "PASSWORD_TOO_SHORT", badRequest.FieldViolations[0].Reason)
+	assert.Equal(t, "PASSWORD_MISSING_DIGIT", badRequest.FieldViolations[1].Reason)
+}
+
+func TestGrpcErrors_AccountLocked(t *testing.T) {
+	until := time.Now().Add(90 * time.Second)
+	st := loginError(t, &domain.AccountLockedError{Until: until})
+
+	assert.Equal(t, codes.ResourceExhausted, st.Code())
+
+	info, ok := statusDetail[*errdetails.ErrorInfo](st)
+	require.True(t, ok)
+	assert.Equal(t, domain.ReasonAccountLocked, info.Reason)
+	assert.Equal(t, until.UTC().Format(time.RFC3339), info.Metadata["locked_until"])
+
+	retryInfo, ok := statusDetail[*errdetails.RetryInfo](st)
+	require.True(t, ok)
+	assert.Greater(t, retryInfo.RetryDelay.AsDuration(), 80*time.Second)
+}
+
+func TestGrpcErrors_HidesUnknownErrors(t *testing.T) {
+	st := loginError(t, errors.New("connection refused to db at 10.0.0.5"))
+
+	assert.Equal(t, codes.Internal, st.Code())
+	assert.Equal(t, "internal server error", st.Message())
+	assert.Empty(t, st.Details())
+}
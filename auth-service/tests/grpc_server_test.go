@@ -0,0 +1,91 @@
+package tests
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	grpcadapter "github.com/natrayanp/GoMicro/auth-service/internal/adapters/grpc"
+	"github.com/natrayanp/GoMicro/auth-service/internal/adapters/metrics"
+	"github.com/natrayanp/GoMicro/auth-service/internal/config"
+	"github.com/natrayanp/GoMicro/auth-service/internal/domain"
+	"github.com/natrayanp/GoMicro/auth-service/internal/ports"
+	pb "github.com/natrayanp/GoMicro/auth-service/proto/auth/v1"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+// panickingService panics on Login; other methods are not used
+type panickingService struct {
+	ports.AuthServicePort
+}
+
+func (panickingService) Login(ctx context.Context, email, password string) (*domain.TokenPair, error) {
+	panic("secret panic detail")
+}
+
+// panickingHealth panics on its streaming Watch method
+type panickingHealth struct {
+	grpc_health_v1.UnimplementedHealthServer
+}
+
+func (panickingHealth) Watch(*grpc_health_v1.HealthCheckRequest, grpc_health_v1.Health_WatchServer) error {
+	panic("stream panic")
+}
+
+func TestGrpcServer_RecoversUnaryPanics(t *testing.T) {
+	logger, buf := newTestLogger(t, "info")
+	m := metrics.NewPrometheus()
+	client := startGrpcServer(t, panickingService{},
+		grpcadapter.WithLogger(logger),
+		grpcadapter.WithMetrics(m),
+	)
+
+	_, err := client.Login(context.Background(), &pb.LoginRequest{Email: "test@example.com", Password: "password123"})
+	st := status.Convert(err)
+	assert.Equal(t, codes.Internal, st.Code())
+	assert.Equal(t, "internal server error", st.Message())
+
+	var panicLog map[string]any
+	for _, entry := range buf.lines(t) {
+		if entry["msg"] == "panic in gRPC method" {
+			panicLog = entry
+		}
+	}
+	require.NotNil(t, panicLog)
+	assert.Equal(t, "secret panic detail", panicLog["panic"])
+	assert.NotEmpty(t, panicLog["request_id"])
+	assert.True(t, strings.Contains(panicLog["stack"].(string), "panickingService.Login"))
+
+	body := scrapeMetrics(t, m)
+	assert.Contains(t, body, `auth_grpc_panics_total{method="/auth.v1.AuthService/Login"} 1`)
+	assert.Contains(t, body, `auth_grpc_requests_total{code="Internal",method="/auth.v1.AuthService/Login"} 1`)
+
+	// The server keeps serving after a panic
+	_, err = client.Login(context.Background(), &pb.LoginRequest{})
+	assert.Equal(t, codes.Internal, status.Code(err))
+}
+
+func TestGrpcServer_RecoversStreamPanics(t *testing.T) {
+	m := metrics.NewPrometheus()
+	server := grpcadapter.NewGrpcServer(&config.Config{}, grpcadapter.NewGrpcAuthHandler(panickingService{}),
+		grpcadapter.WithMetrics(m),
+	)
+	grpc_health_v1.RegisterHealthServer(server.GrpcServer(), panickingHealth{})
+
+	client := grpc_health_v1.NewHealthClient(serveBufconn(t, server))
+
+	stream, err := client.Watch(context.Background(), &grpc_health_v1.HealthCheckRequest{})
+	require.NoError(t, err)
+
+	_, err = stream.Recv()
+	st := status.Convert(err)
+	assert.Equal(t, codes.Internal, st.Code())
+	assert.Equal(t, "internal server error", st.Message())
+
+	assert.Contains(t, scrapeMetrics(t, m), `auth_grpc_panics_total{method="/grpc.health.v1.Health/Watch"} 1`)
+}
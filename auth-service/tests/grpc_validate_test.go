@@ -0,0 +1,122 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/natrayanp/GoMicro/auth-service/internal/auth/jwt"
+	"github.com/natrayanp/GoMicro/auth-service/internal/core"
+	"github.com/natrayanp/GoMicro/auth-service/internal/domain"
+	"github.com/natrayanp/GoMicro/auth-service/internal/ports"
+	pb "github.com/natrayanp/GoMicro/auth-service/proto/auth/v1"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestGrpcServer_ValidateReturnsClaims(t *testing.T) {
+	f := newAuthServiceFixture(nil, core.WithScopes([]string{"orders:read"}))
+	client := startGrpcServer(t, f.service)
+	ctx := context.Background()
+
+	user := registerAndLogin(t, client, "test@example.com")
+
+	resp, err := client.Validate(ctx, &pb.ValidateRequest{Token: user.tokens.AccessToken})
+	require.NoError(t, err)
+	assert.True(t, resp.Valid)
+	assert.Equal(t, user.id, resp.UserId)
+	assert.NotEmpty(t, resp.SessionId)
+	assert.Equal(t, []string{"orders:read"}, resp.Scopes)
+	assert.Empty(t, resp.Roles)
+	assert.Equal(t, pb.ValidateFailureReason_VALIDATE_FAILURE_REASON_UNSPECIFIED, resp.FailureReason)
+	assert.InDelta(t, time.Now().Add(15*time.Minute).Unix(), resp.ExpiresAt, 2)
+	assert.InDelta(t, time.Now().Unix(), resp.IssuedAt, 2)
+
+	// A refreshed pair belongs to the same session
+	refreshed, err := client.Refresh(ctx, &pb.RefreshRequest{RefreshToken: user.tokens.RefreshToken})
+	require.NoError(t, err)
+	next, err := client.Validate(ctx, &pb.ValidateRequest{Token: refreshed.AccessToken})
+	require.NoError(t, err)
+	assert.Equal(t, resp.SessionId, next.SessionId)
+
+	// A new login starts a new one
+	login, err := client.Login(ctx, &pb.LoginRequest{Email: "test@example.com", Password: "password123"})
+	require.NoError(t, err)
+	other, err := client.Validate(ctx, &pb.ValidateRequest{Token: login.AccessToken})
+	require.NoError(t, err)
+	assert.NotEqual(t, resp.SessionId, other.SessionId)
+}
+
+func TestGrpcServer_ValidateFailureReasons(t *testing.T) {
+	client := startGrpcServer(t, newAuthServiceFixture(nil, withTestDenylist()).service)
+	ctx := context.Background()
+
+	user := registerAndLogin(t, client, "test@example.com")
+	expired, err := jwt.NewJWTProvider("test-secret", -time.Minute, time.Hour).GenerateAccessToken(user.id)
+	require.NoError(t, err)
+
+	second, err := client.Login(ctx, &pb.LoginRequest{Email: "test@example.com", Password: "password123"})
+	require.NoError(t, err)
+	_, err = client.Logout(ctx, &pb.LogoutRequest{RefreshToken: second.RefreshToken, AccessToken: second.AccessToken})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name   string
+		token  string
+		reason pb.ValidateFailureReason
+	}{
+		{"Expired", expired, pb.ValidateFailureReason_VALIDATE_FAILURE_REASON_EXPIRED},
+		{"Malformed", "not-a-token", pb.ValidateFailureReason_VALIDATE_FAILURE_REASON_MALFORMED},
+		{"Revoked", second.AccessToken, pb.ValidateFailureReason_VALIDATE_FAILURE_REASON_REVOKED},
+		{"WrongType", user.tokens.RefreshToken, pb.ValidateFailureReason_VALIDATE_FAILURE_REASON_WRONG_TYPE},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := client.Validate(ctx, &pb.ValidateRequest{Token: tt.token})
+			require.NoError(t, err)
+			assert.False(t, resp.Valid)
+			assert.Equal(t, tt.reason, resp.FailureReason)
+			assert.Empty(t, resp.UserId)
+		})
+	}
+}
+
+func TestGrpcServer_ValidateSurfacesCheckFailures(t *testing.T) {
+	client := startGrpcServer(t, failingService{err: errors.New("denylist unavailable")})
+
+	// Not being able to check a token is not the same as a bad token
+	_, err := client.Validate(context.Background(), &pb.ValidateRequest{Token: "token"})
+	assert.Equal(t, codes.Internal, status.Code(err))
+}
+
+func TestAuthService_AdminsGetAdminRole(t *testing.T) {
+	ctx := context.Background()
+
+	// Register first so the admin's ID is known
+	plain := newAuthServiceFixture(nil)
+	admin, err := plain.service.Register(ctx, "admin@example.com", "password123")
+	require.NoError(t, err)
+	_, err = plain.service.Register(ctx, "user@example.com", "password123")
+	require.NoError(t, err)
+
+	service := newAuthServiceFixtureOn(ports.TxRepositories{Users: plain.users}, nil,
+		core.WithAdmins([]string{admin.ID}),
+	).service
+
+	tokens, err := service.Login(ctx, "admin@example.com", "password123")
+	require.NoError(t, err)
+	claims, err := service.ValidateToken(ctx, tokens.AccessToken)
+	require.NoError(t, err)
+	assert.Equal(t, []string{domain.RoleAdmin}, claims.Roles)
+
+	tokens, err = service.Login(ctx, "user@example.com", "password123")
+	require.NoError(t, err)
+	claims, err = service.ValidateToken(ctx, tokens.AccessToken)
+	require.NoError(t, err)
+	assert.Empty(t, claims.Roles)
+}
@@ -0,0 +1,256 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"io"
+	"log/slog"
+	"net"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/natrayanp/GoMicro/auth-service/internal/adapters/events"
+	grpcadapter "github.com/natrayanp/GoMicro/auth-service/internal/adapters/grpc"
+	"github.com/natrayanp/GoMicro/auth-service/internal/adapters/metrics"
+	"github.com/natrayanp/GoMicro/auth-service/internal/auth/jwt"
+	"github.com/natrayanp/GoMicro/auth-service/internal/auth/opaque"
+	"github.com/natrayanp/GoMicro/auth-service/internal/config"
+	"github.com/natrayanp/GoMicro/auth-service/internal/core"
+	"github.com/natrayanp/GoMicro/auth-service/internal/domain"
+	"github.com/natrayanp/GoMicro/auth-service/internal/logging"
+	"github.com/natrayanp/GoMicro/auth-service/internal/ports"
+	"github.com/natrayanp/GoMicro/auth-service/internal/storage/memory"
+	pb "github.com/natrayanp/GoMicro/auth-service/proto/auth/v1"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+type authServiceFixture struct {
+	service   *core.AuthService
+	users     ports.UserRepository
+	tokens    ports.TokenRepository
+	publisher *events.MemoryPublisher
+}
+
+// newAuthServiceFixture builds a service on memory storage. A nil provider
+// means the default test JWT provider; opts are applied after the unit of
+// work.
+func newAuthServiceFixture(provider ports.TokenProviderPort, opts ...core.Option) *authServiceFixture {
+	return newAuthServiceFixtureOn(ports.TxRepositories{}, provider, opts...)
+}
+
+// newAuthServiceFixtureOn is newAuthServiceFixture over the given
+// repositories, for tests that wrap them or share them between services.
+// Missing user and token repositories are created in memory.
+func newAuthServiceFixtureOn(repos ports.TxRepositories, provider ports.TokenProviderPort, opts ...core.Option) *authServiceFixture {
+	if repos.Users == nil {
+		repos.Users = memory.NewUserRepository()
+	}
+	if repos.Tokens == nil {
+		repos.Tokens = memory.NewTokenRepository()
+	}
+
+	f := &authServiceFixture{
+		users:     repos.Users,
+		tokens:    repos.Tokens,
+		publisher: events.NewMemoryPublisher(),
+	}
+
+	if provider == nil {
+		provider = jwt.NewJWTProvider("test-secret", 15*time.Minute, 7*24*time.Hour)
+	}
+
+	opts = append([]core.Option{core.WithUnitOfWork(memory.NewUnitOfWork(repos))}, opts...)
+	f.service = core.NewAuthService(f.users, f.tokens, provider, f.publisher, opts...)
+
+	return f
+}
+
+func (f *authServiceFixture) login(t *testing.T) (*domain.User, *domain.TokenPair) {
+	t.Helper()
+	ctx := context.Background()
+
+	user, err := f.service.Register(ctx, "test@example.com", "password123")
+	assert.NoError(t, err)
+
+	tokenPair, err := f.service.Login(ctx, "test@example.com", "password123")
+	assert.NoError(t, err)
+
+	return user, tokenPair
+}
+
+// withTestDenylist enables the access token denylist for the fixture's
+// 15 minute access tokens
+func withTestDenylist() core.Option {
+	return core.WithAccessTokenDenylist(memory.NewAccessTokenDenylistRepository(), 15*time.Minute)
+}
+
+// newOpaqueProvider issues opaque refresh tokens with the test JWT access
+// tokens
+func newOpaqueProvider() *opaque.Provider {
+	accessTokens := jwt.NewJWTProvider("test-secret-key", 15*time.Minute, 7*24*time.Hour)
+	return opaque.NewProvider(accessTokens, 7*24*time.Hour)
+}
+
+// startGrpcServer serves service over an in-memory connection and returns a
+// client for it
+func startGrpcServer(t *testing.T, service ports.AuthServicePort, opts ...grpcadapter.ServerOption) pb.AuthServiceClient {
+	t.Helper()
+
+	return startGrpcServerWithConfig(t, &config.Config{}, service, opts...)
+}
+
+// startGrpcServerWithConfig is startGrpcServer with a custom config
+func startGrpcServerWithConfig(t *testing.T, cfg *config.Config, service ports.AuthServicePort, opts ...grpcadapter.ServerOption) pb.AuthServiceClient {
+	t.Helper()
+
+	server := grpcadapter.NewGrpcServer(cfg, grpcadapter.NewGrpcAuthHandler(service), opts...)
+	server.RegisterService()
+
+	return pb.NewAuthServiceClient(serveBufconn(t, server))
+}
+
+// serveBufconn serves a configured server over an in-memory connection
+func serveBufconn(t *testing.T, server *grpcadapter.GrpcServer) *grpc.ClientConn {
+	t.Helper()
+
+	return serveGrpcServer(t, server.GrpcServer())
+}
+
+// serveGrpcServer serves any gRPC server over an in-memory connection
+func serveGrpcServer(t *testing.T, server *grpc.Server) *grpc.ClientConn {
+	t.Helper()
+
+	lis := bufconn.Listen(1 << 20)
+	go server.Serve(lis)
+	t.Cleanup(server.GracefulStop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	return conn
+}
+
+// authTestUser is a registered user with a fresh token pair
+type authTestUser struct {
+	id     string
+	tokens *pb.LoginResponse
+}
+
+func registerAndLogin(t *testing.T, client pb.AuthServiceClient, email string) authTestUser {
+	t.Helper()
+	ctx := context.Background()
+
+	reg, err := client.Register(ctx, &pb.RegisterRequest{Email: email, Password: "password123"})
+	require.NoError(t, err)
+
+	login, err := client.Login(ctx, &pb.LoginRequest{Email: email, Password: "password123"})
+	require.NoError(t, err)
+
+	return authTestUser{id: reg.UserId, tokens: login}
+}
+
+func withBearer(token string) context.Context {
+	return metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer "+token)
+}
+
+func assertReason(t *testing.T, err error, code codes.Code, reason string) {
+	t.Helper()
+
+	st := status.Convert(err)
+	assert.Equal(t, code, st.Code())
+
+	info, ok := statusDetail[*errdetails.ErrorInfo](st)
+	require.True(t, ok)
+	assert.Equal(t, reason, info.Reason)
+}
+
+// statusDetail returns the first detail of type T attached to st
+func statusDetail[T any](st *status.Status) (T, bool) {
+	for _, d := range st.Details() {
+		if detail, ok := d.(T); ok {
+			return detail, true
+		}
+	}
+	var zero T
+	return zero, false
+}
+
+func scrapeMetrics(t *testing.T, m *metrics.Prometheus) string {
+	t.Helper()
+
+	rec := httptest.NewRecorder()
+	m.Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	body, err := io.ReadAll(rec.Result().Body)
+	require.NoError(t, err)
+
+	return string(body)
+}
+
+// syncBuffer is a bytes.Buffer safe for concurrent log writes
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+// lines decodes every JSON log line written so far
+func (b *syncBuffer) lines(t *testing.T) []map[string]any {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var lines []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(b.buf.String()), "\n") {
+		var entry map[string]any
+		require.NoError(t, json.Unmarshal([]byte(line), &entry))
+		lines = append(lines, entry)
+	}
+	return lines
+}
+
+func newTestLogger(t *testing.T, level string) (*slog.Logger, *syncBuffer) {
+	buf := &syncBuffer{}
+	logger, err := logging.New(buf, &config.LogConfig{Level: level, Format: "json"})
+	require.NoError(t, err)
+	return logger, buf
+}
+
+func writePrivateKeyPEM(t *testing.T, key any) string {
+	t.Helper()
+
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	assert.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "signing.pem")
+	err = os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0o600)
+	assert.NoError(t, err)
+
+	return path
+}
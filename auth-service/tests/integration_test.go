@@ -5,7 +5,11 @@ import (
 	"testing"
 
 	"github.com/natrayanp/GoMicro/auth-service/internal/config"
+	"github.com/natrayanp/GoMicro/auth-service/internal/domain"
+	"github.com/natrayanp/GoMicro/auth-service/internal/ports"
 	"github.com/natrayanp/GoMicro/auth-service/internal/storage/postgres"
+	"github.com/natrayanp/GoMicro/auth-service/internal/storage/storagetest"
+	"github.com/natrayanp/GoMicro/auth-service/sql/schema"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/testcontainers/testcontainers-go"
@@ -79,3 +83,158 @@ func TestDatabaseIntegration(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, 1, count)
 }
+
+func TestPostgresRepositoryContract(t *testing.T) {
+	db := startPostgres(t)
+	applySchema(t, db)
+
+	// Every subtest starts from empty tables
+	reset := func(t *testing.T) {
+		_, err := db.Pool.Exec(context.Background(), "TRUNCATE users, refresh_tokens, password_history, login_attempts, revoked_access_tokens, access_token_watermarks CASCADE")
+		assert.NoError(t, err)
+	}
+
+	t.Run("Users", func(t *testing.T) {
+		storagetest.RunUserRepositoryTests(t, func(t *testing.T) ports.UserRepository {
+			reset(t)
+			return postgres.NewUserRepository(db)
+		})
+	})
+
+	t.Run("Tokens", func(t *testing.T) {
+		storagetest.RunTokenRepositoryTests(t, func(t *testing.T) (ports.UserRepository, ports.TokenRepository) {
+			reset(t)
+			return postgres.NewUserRepository(db), postgres.NewTokenRepository(db)
+		})
+	})
+
+	t.Run("PasswordHistory", func(t *testing.T) {
+		storagetest.RunPasswordHistoryRepositoryTests(t, func(t *testing.T) (ports.UserRepository, ports.PasswordHistoryRepository) {
+			reset(t)
+			return postgres.NewUserRepository(db), postgres.NewPasswordHistoryRepository(db)
+		})
+	})
+
+	t.Run("LoginAttempts", func(t *testing.T) {
+		storagetest.RunLoginAttemptRepositoryTests(t, func(t *testing.T) ports.LoginAttemptRepository {
+			reset(t)
+			return postgres.NewLoginAttemptRepository(db)
+		})
+	})
+
+	t.Run("AccessTokenDenylist", func(t *testing.T) {
+		storagetest.RunAccessTokenDenylistRepositoryTests(t, func(t *testing.T) (ports.UserRepository, ports.AccessTokenDenylistRepository) {
+			reset(t)
+			return postgres.NewUserRepository(db), postgres.NewAccessTokenDenylistRepository(db)
+		})
+	})
+}
+
+func TestPostgresUserRepository_ConnectionErrorsAreNotMapped(t *testing.T) {
+	db := startPostgres(t)
+	applySchema(t, db)
+
+	repo := postgres.NewUserRepository(db)
+	db.Close()
+
+	// A closed pool is an infrastructure failure, not a missing user
+	_, err := repo.GetUserByEmail(context.Background(), "alice@example.com")
+	assert.Error(t, err)
+	assert.NotErrorIs(t, err, domain.ErrUserNotFound)
+}
+
+func TestPostgresMigrations(t *testing.T) {
+	db := startPostgres(t)
+	ctx := context.Background()
+
+	migrator, err := postgres.NewMigrator(db, schema.FS)
+	assert.NoError(t, err)
+	total := len(migrator.Migrations())
+
+	applied, err := migrator.Up(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, applied, total)
+
+	// Nothing left to apply
+	applied, err = migrator.Up(ctx)
+	assert.NoError(t, err)
+	assert.Empty(t, applied)
+
+	statuses, err := migrator.Status(ctx)
+	assert.NoError(t, err)
+	for _, status := range statuses {
+		assert.NotNil(t, status.AppliedAt, status.Name)
+	}
+
+	// Roll everything back, then forward again
+	for i := 0; i < total; i++ {
+		migration, err := migrator.Down(ctx)
+		assert.NoError(t, err)
+		assert.NotNil(t, migration)
+	}
+
+	migration, err := migrator.Down(ctx)
+	assert.NoError(t, err)
+	assert.Nil(t, migration)
+
+	applied, err = migrator.Up(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, applied, total)
+}
+
+// startPostgres runs a throwaway PostgreSQL container for the test
+func startPostgres(t *testing.T) *postgres.DB {
+	t.Helper()
+	ctx := context.Background()
+
+	req := testcontainers.ContainerRequest{
+		Image:        "postgres:15-alpine",
+		ExposedPorts: []string{"5432/tcp"},
+		Env: map[string]string{
+			"POSTGRES_USER":     "test",
+			"POSTGRES_PASSWORD": "test",
+			"POSTGRES_DB":       "testdb",
+		},
+		WaitingFor: wait.ForLog("database system is ready to accept connections").WithOccurrence(2),
+	}
+
+	container, err := testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{
+		ContainerRequest: req,
+		Started:          true,
+	})
+	if err != nil {
+		t.Skip("Could not start PostgreSQL container:", err)
+	}
+	t.Cleanup(func() { container.Terminate(ctx) })
+
+	host, err := container.Host(ctx)
+	assert.NoError(t, err)
+
+	port, err := container.MappedPort(ctx, "5432")
+	assert.NoError(t, err)
+
+	db, err := postgres.NewConnection(&config.DatabaseConfig{
+		Host:     host,
+		Port:     port.Int(),
+		User:     "test",
+		Password: "test",
+		Name:     "testdb",
+	})
+	if err != nil {
+		t.Skip("Could not connect to test database:", err)
+	}
+	t.Cleanup(db.Close)
+
+	return db
+}
+
+// applySchema migrates the test database to the latest version
+func applySchema(t *testing.T, db *postgres.DB) {
+	t.Helper()
+
+	migrator, err := postgres.NewMigrator(db, schema.FS)
+	assert.NoError(t, err)
+
+	_, err = migrator.Up(context.Background())
+	assert.NoError(t, err)
+}
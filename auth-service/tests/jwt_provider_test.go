@@ -1,47 +1,347 @@
 package tests
 
 import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
 	"testing"
 	"time"
 
 	"github.com/natrayanp/GoMicro/auth-service/internal/auth/jwt"
+	"github.com/natrayanp/GoMicro/auth-service/internal/config"
+	"github.com/natrayanp/GoMicro/auth-service/internal/domain"
 
+	gojwt "github.com/golang-jwt/jwt/v5"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestJWTProvider_GenerateAndValidate(t *testing.T) {
-	provider := jwt.NewProvider("test-secret-key", 15*time.Minute, 7*24*time.Hour)
+	provider := jwt.NewJWTProvider("test-secret-key", 15*time.Minute, 7*24*time.Hour)
 
 	// Generate tokens
-	tokenPair, err := provider.GenerateTokenPair("user123")
+	tokenPair, err := provider.GenerateTokenPair(domain.TokenGrant{UserID: "user123"})
 	assert.NoError(t, err)
 	assert.NotEmpty(t, tokenPair.AccessToken)
 	assert.NotEmpty(t, tokenPair.RefreshToken)
 
 	// Validate access token
-	userID, tokenType, err := provider.ValidateToken(tokenPair.AccessToken)
+	accessClaims, err := provider.ValidateToken(tokenPair.AccessToken)
 	assert.NoError(t, err)
-	assert.Equal(t, "user123", userID)
-	assert.Equal(t, "access", tokenType)
+	assert.Equal(t, "user123", accessClaims.Subject)
+	assert.Equal(t, domain.TokenTypeAccess, accessClaims.Type)
 
 	// Validate refresh token
-	userID, tokenType, err = provider.ValidateToken(tokenPair.RefreshToken)
+	refreshClaims, err := provider.ValidateToken(tokenPair.RefreshToken)
 	assert.NoError(t, err)
-	assert.Equal(t, "user123", userID)
-	assert.Equal(t, "refresh", tokenType)
+	assert.Equal(t, "user123", refreshClaims.Subject)
+	assert.Equal(t, domain.TokenTypeRefresh, refreshClaims.Type)
+
+	// Every token gets its own jti and the standard registered claims
+	assert.NotEmpty(t, accessClaims.ID)
+	assert.NotEqual(t, accessClaims.ID, refreshClaims.ID)
+	assert.Equal(t, "auth-service", accessClaims.Issuer)
+	assert.Equal(t, []string{"auth-service"}, accessClaims.Audience)
+	assert.False(t, accessClaims.IssuedAt.IsZero())
+	assert.False(t, accessClaims.NotBefore.IsZero())
+	assert.True(t, accessClaims.ExpiresAt.After(accessClaims.IssuedAt))
+}
+
+func TestJWTProvider_GrantClaims(t *testing.T) {
+	provider := jwt.NewJWTProvider("test-secret-key", 15*time.Minute, 7*24*time.Hour)
+
+	tokenPair, err := provider.GenerateTokenPair(domain.TokenGrant{
+		UserID:    "user123",
+		SessionID: "session-1",
+		Roles:     []string{domain.RoleAdmin},
+		Scopes:    []string{"orders:read", "orders:write"},
+	})
+	require.NoError(t, err)
+
+	access, err := provider.ValidateToken(tokenPair.AccessToken)
+	require.NoError(t, err)
+	assert.Equal(t, "session-1", access.SessionID)
+	assert.Equal(t, []string{domain.RoleAdmin}, access.Roles)
+	assert.Equal(t, []string{"orders:read", "orders:write"}, access.Scopes)
+
+	// Refresh tokens only carry the session
+	refresh, err := provider.ValidateToken(tokenPair.RefreshToken)
+	require.NoError(t, err)
+	assert.Equal(t, "session-1", refresh.SessionID)
+	assert.Empty(t, refresh.Roles)
+	assert.Empty(t, refresh.Scopes)
 }
 
 func TestJWTProvider_InvalidToken(t *testing.T) {
-	provider := jwt.NewProvider("test-secret-key", 15*time.Minute, 7*24*time.Hour)
+	provider := jwt.NewJWTProvider("test-secret-key", 15*time.Minute, 7*24*time.Hour)
 
 	// Invalid token
-	_, _, err := provider.ValidateToken("invalid.token.here")
+	_, err := provider.ValidateToken("invalid.token.here")
 	assert.Error(t, err)
 
 	// Wrong secret
-	provider2 := jwt.NewProvider("different-secret", 15*time.Minute, 7*24*time.Hour)
-	tokenPair, _ := provider.GenerateTokenPair("user123")
+	provider2 := jwt.NewJWTProvider("different-secret", 15*time.Minute, 7*24*time.Hour)
+	tokenPair, _ := provider.GenerateTokenPair(domain.TokenGrant{UserID: "user123"})
+
+	_, err = provider2.ValidateToken(tokenPair.AccessToken)
+	assert.Error(t, err)
+}
+
+func TestJWTProvider_AsymmetricSigning(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+
+	tests := []struct {
+		method string
+		key    any
+		kty    string
+	}{
+		{"RS256", rsaKey, "RSA"},
+		{"ES256", ecKey, "EC"},
+		{"EdDSA", edKey, "OKP"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.method, func(t *testing.T) {
+			provider, err := jwt.NewProviderFromConfig(&config.JWTConfig{
+				SigningMethod:  tt.method,
+				PrivateKeyFile: writePrivateKeyPEM(t, tt.key),
+				AccessExpiry:   15 * time.Minute,
+				RefreshExpiry:  7 * 24 * time.Hour,
+			})
+			assert.NoError(t, err)
+
+			token, err := provider.GenerateAccessToken("user123")
+			assert.NoError(t, err)
+
+			claims, err := provider.ValidateToken(token)
+			assert.NoError(t, err)
+			assert.Equal(t, "user123", claims.Subject)
+
+			// The kid header must match the single published key
+			jwks := provider.JWKS()
+			assert.Len(t, jwks.Keys, 1)
+			assert.Equal(t, tt.kty, jwks.Keys[0].KeyType)
+			assert.Equal(t, tt.method, jwks.Keys[0].Algorithm)
+
+			parsed, _, err := gojwt.NewParser().ParseUnverified(token, gojwt.MapClaims{})
+			assert.NoError(t, err)
+			assert.Equal(t, jwks.Keys[0].KeyID, parsed.Header["kid"])
+		})
+	}
+}
+
+func TestJWTProvider_JWKSOmitsSharedSecret(t *testing.T) {
+	provider := jwt.NewJWTProvider("test-secret-key", 15*time.Minute, 7*24*time.Hour)
+
+	rec := httptest.NewRecorder()
+	provider.JWKSHandler()(rec, httptest.NewRequest(http.MethodGet, "/.well-known/jwks.json", nil))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"keys":[]}`, rec.Body.String())
+}
 
-	_, _, err = provider2.ValidateToken(tokenPair.AccessToken)
+func TestJWTProvider_RejectsAlgorithmSwitch(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+
+	provider, err := jwt.NewProviderFromConfig(&config.JWTConfig{
+		SigningMethod:  "RS256",
+		PrivateKeyFile: writePrivateKeyPEM(t, rsaKey),
+		AccessExpiry:   15 * time.Minute,
+	})
+	assert.NoError(t, err)
+
+	// An HS256 token signed with the public key bytes must not verify
+	der, err := x509.MarshalPKIXPublicKey(&rsaKey.PublicKey)
+	assert.NoError(t, err)
+	forged, err := gojwt.NewWithClaims(gojwt.SigningMethodHS256, gojwt.MapClaims{"sub": "attacker"}).SignedString(der)
+	assert.NoError(t, err)
+
+	_, err = provider.ValidateToken(forged)
+	assert.Error(t, err)
+}
+
+func TestJWTProvider_KeyRotation(t *testing.T) {
+	oldKey := jwt.NewHMACKey("old-secret", "")
+	provider := jwt.NewJWTProviderWithKeyring(jwt.NewKeyring(oldKey, time.Hour), 15*time.Minute, 7*24*time.Hour)
+
+	oldToken, err := provider.GenerateAccessToken("user123")
+	assert.NoError(t, err)
+
+	newKey := jwt.NewHMACKey("new-secret", "")
+	provider.Keyring().Promote(newKey)
+	assert.Equal(t, newKey.ID, provider.Keyring().Active().ID)
+
+	// Tokens signed before the rotation verify during the grace period
+	claims, err := provider.ValidateToken(oldToken)
+	assert.NoError(t, err)
+	assert.Equal(t, "user123", claims.Subject)
+
+	newToken, err := provider.GenerateAccessToken("user456")
+	assert.NoError(t, err)
+	claims, err = provider.ValidateToken(newToken)
+	assert.NoError(t, err)
+	assert.Equal(t, "user456", claims.Subject)
+
+	// A provider that never held the old key rejects the old token
+	fresh := jwt.NewJWTProviderWithKey(newKey, 15*time.Minute, 7*24*time.Hour)
+	_, err = fresh.ValidateToken(oldToken)
 	assert.Error(t, err)
 }
+
+func TestJWTProvider_RetiredKeyExpiresAfterGracePeriod(t *testing.T) {
+	oldKey := jwt.NewHMACKey("old-secret", "")
+	provider := jwt.NewJWTProviderWithKeyring(jwt.NewKeyring(oldKey, 0), 15*time.Minute, 7*24*time.Hour)
+
+	oldToken, err := provider.GenerateAccessToken("user123")
+	assert.NoError(t, err)
+
+	provider.Keyring().Promote(jwt.NewHMACKey("new-secret", ""))
+
+	_, err = provider.ValidateToken(oldToken)
+	assert.Error(t, err)
+}
+
+func TestJWTProvider_PreviousSecretsFromConfig(t *testing.T) {
+	old := jwt.NewJWTProvider("old-secret", 15*time.Minute, 7*24*time.Hour)
+	oldToken, err := old.GenerateAccessToken("user123")
+	assert.NoError(t, err)
+
+	provider, err := jwt.NewProviderFromConfig(&config.JWTConfig{
+		SecretKey:       "new-secret",
+		SigningMethod:   "HS256",
+		AccessExpiry:    15 * time.Minute,
+		KeyGracePeriod:  time.Hour,
+		PreviousSecrets: []string{"old-secret"},
+	})
+	assert.NoError(t, err)
+
+	claims, err := provider.ValidateToken(oldToken)
+	assert.NoError(t, err)
+	assert.Equal(t, "user123", claims.Subject)
+}
+
+func TestJWTProvider_WatchKeyFile(t *testing.T) {
+	firstKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	path := writePrivateKeyPEM(t, firstKey)
+
+	cfg := &config.JWTConfig{
+		SigningMethod:    "ES256",
+		PrivateKeyFile:   path,
+		AccessExpiry:     15 * time.Minute,
+		KeyGracePeriod:   time.Hour,
+		KeyWatchInterval: 10 * time.Millisecond,
+	}
+	provider, err := jwt.NewProviderFromConfig(cfg)
+	assert.NoError(t, err)
+	firstKID := provider.Keyring().Active().ID
+
+	oldToken, err := provider.GenerateAccessToken("user123")
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go provider.WatchKeyFile(ctx, cfg)
+
+	secondKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	der, err := x509.MarshalPKCS8PrivateKey(secondKey)
+	assert.NoError(t, err)
+	err = os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0o600)
+	assert.NoError(t, err)
+
+	assert.Eventually(t, func() bool {
+		return provider.Keyring().Active().ID != firstKID
+	}, time.Second, 10*time.Millisecond)
+
+	// Both keys are published while the old one is in its grace period
+	assert.Len(t, provider.JWKS().Keys, 2)
+
+	_, err = provider.ValidateToken(oldToken)
+	assert.NoError(t, err)
+}
+
+func TestJWTProvider_RejectsForeignIssuerAndAudience(t *testing.T) {
+	issuer, err := jwt.NewProviderFromConfig(&config.JWTConfig{
+		SecretKey:    "shared-secret",
+		AccessExpiry: 15 * time.Minute,
+		Issuer:       "other-service",
+		Audience:     []string{"other-audience"},
+	})
+	assert.NoError(t, err)
+
+	token, err := issuer.GenerateAccessToken("user123")
+	assert.NoError(t, err)
+
+	// Same key, but the issuer and audience do not match
+	provider := jwt.NewJWTProvider("shared-secret", 15*time.Minute, 7*24*time.Hour)
+	_, err = provider.ValidateToken(token)
+	assert.ErrorIs(t, err, domain.ErrInvalidToken)
+}
+
+func TestJWTProvider_ExpiredToken(t *testing.T) {
+	provider := jwt.NewJWTProvider("test-secret-key", -time.Minute, 7*24*time.Hour)
+
+	token, err := provider.GenerateAccessToken("user123")
+	assert.NoError(t, err)
+
+	_, err = provider.ValidateToken(token)
+	assert.ErrorIs(t, err, domain.ErrTokenExpired)
+}
+
+func TestJWTProvider_RejectsTokenWithoutType(t *testing.T) {
+	provider := jwt.NewJWTProvider("test-secret-key", 15*time.Minute, 7*24*time.Hour)
+
+	// Correctly signed, but missing the typ and jti claims
+	untyped := gojwt.NewWithClaims(gojwt.SigningMethodHS256, gojwt.MapClaims{
+		"sub": "user123",
+		"iss": "auth-service",
+		"aud": "auth-service",
+		"exp": time.Now().Add(time.Minute).Unix(),
+	})
+	untyped.Header["kid"] = provider.Keyring().Active().ID
+	token, err := untyped.SignedString([]byte("test-secret-key"))
+	assert.NoError(t, err)
+
+	_, err = provider.ValidateToken(token)
+	assert.ErrorIs(t, err, domain.ErrInvalidToken)
+}
+
+func TestJWTProvider_HashRefreshToken(t *testing.T) {
+	newProvider := func(pepper string) *jwt.Provider {
+		provider, err := jwt.NewProviderFromConfig(&config.JWTConfig{
+			SecretKey:     "test-secret-key",
+			AccessExpiry:  15 * time.Minute,
+			RefreshExpiry: 7 * 24 * time.Hour,
+			RefreshPepper: pepper,
+		})
+		assert.NoError(t, err)
+		return provider
+	}
+
+	provider := newProvider("pepper-one")
+	token, err := provider.GenerateRefreshToken("user123")
+	assert.NoError(t, err)
+
+	hash := provider.HashRefreshToken(token)
+	assert.Regexp(t, "^[0-9a-f]{64}$", hash)
+	assert.NotContains(t, hash, token)
+	assert.Equal(t, hash, provider.HashRefreshToken(token))
+
+	// A different pepper yields an unrelated hash
+	assert.NotEqual(t, hash, newProvider("pepper-two").HashRefreshToken(token))
+}
@@ -0,0 +1,156 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/natrayanp/GoMicro/auth-service/internal/core"
+	"github.com/natrayanp/GoMicro/auth-service/internal/domain"
+	"github.com/natrayanp/GoMicro/auth-service/internal/storage/memory"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLockoutPolicy_LockDuration(t *testing.T) {
+	policy := domain.LockoutPolicy{
+		MaxAttempts:  3,
+		BaseDuration: time.Minute,
+		MaxDuration:  5 * time.Minute,
+	}
+
+	tests := []struct {
+		failed   int
+		expected time.Duration
+	}{
+		{1, 0},
+		{2, 0},
+		{3, time.Minute},
+		{4, 2 * time.Minute},
+		{5, 4 * time.Minute},
+		{6, 5 * time.Minute},
+		{100, 5 * time.Minute},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, policy.LockDuration(tt.failed), "failed=%d", tt.failed)
+	}
+
+	assert.Zero(t, domain.LockoutPolicy{}.LockDuration(100), "zero policy never locks")
+}
+
+func TestAccountLockedError(t *testing.T) {
+	err := &domain.AccountLockedError{Until: time.Now().Add(90 * time.Second)}
+
+	assert.ErrorIs(t, err, domain.ErrAccountLocked)
+	assert.Equal(t, 90*time.Second, err.RetryAfter())
+
+	expired := &domain.AccountLockedError{Until: time.Now().Add(-time.Second)}
+	assert.Zero(t, expired.RetryAfter())
+}
+
+func TestAuthService_LockoutAfterFailedLogins(t *testing.T) {
+	attempts := memory.NewLoginAttemptRepository()
+	f := newAuthServiceFixture(nil, core.WithLockout(attempts, domain.LockoutPolicy{
+		MaxAttempts:  3,
+		BaseDuration: 200 * time.Millisecond,
+		MaxDuration:  time.Second,
+		ResetAfter:   time.Hour,
+	}))
+	service := f.service
+	ctx := context.Background()
+
+	user, err := service.Register(ctx, "test@example.com", "password123")
+	require.NoError(t, err)
+
+	for i := 0; i < 2; i++ {
+		_, err := service.Login(ctx, "test@example.com", "wrong-password")
+		assert.ErrorIs(t, err, domain.ErrInvalidCredentials)
+	}
+
+	// The third failure locks the account
+	_, err = service.Login(ctx, "test@example.com", "wrong-password")
+	assert.ErrorIs(t, err, domain.ErrAccountLocked)
+
+	// Even the right password is refused while locked
+	_, err = service.Login(ctx, "test@example.com", "password123")
+	var lockedErr *domain.AccountLockedError
+	require.ErrorAs(t, err, &lockedErr)
+	assert.True(t, lockedErr.Until.After(time.Now()))
+
+	assert.Eventually(t, func() bool {
+		for _, event := range f.publisher.Events() {
+			if event.Name != domain.EventAccountLocked {
+				continue
+			}
+			locked := event.Data.(domain.AccountLockedEvent)
+			return locked.UserID == user.ID && locked.FailedAttempts == 3
+		}
+		return false
+	}, time.Second, 10*time.Millisecond)
+
+	// Once the lock expires a successful login clears the failures
+	time.Sleep(time.Until(lockedErr.Until))
+
+	_, err = service.Login(ctx, "test@example.com", "password123")
+	assert.NoError(t, err)
+
+	record, err := attempts.GetLoginAttempts(ctx, domain.LoginKey("test@example.com"))
+	require.NoError(t, err)
+	assert.Zero(t, record.FailedCount)
+	assert.Nil(t, record.LockedUntil)
+}
+
+func TestAuthService_LockoutBacksOffExponentially(t *testing.T) {
+	service := newAuthServiceFixture(nil, core.WithLockout(memory.NewLoginAttemptRepository(), domain.LockoutPolicy{
+		MaxAttempts:  1,
+		BaseDuration: 50 * time.Millisecond,
+		MaxDuration:  time.Hour,
+		ResetAfter:   time.Hour,
+	})).service
+	ctx := context.Background()
+
+	_, err := service.Register(ctx, "test@example.com", "password123")
+	require.NoError(t, err)
+
+	var previous time.Duration
+	for i := 0; i < 3; i++ {
+		_, err := service.Login(ctx, "test@example.com", "wrong-password")
+
+		var lockedErr *domain.AccountLockedError
+		require.ErrorAs(t, err, &lockedErr)
+
+		// Measured from the reply, so the password hash time is left out
+		lock := time.Until(lockedErr.Until)
+		assert.Greater(t, lock, previous)
+		previous = lock
+
+		time.Sleep(time.Until(lockedErr.Until))
+	}
+}
+
+func TestAuthService_LockoutTreatsUnknownEmailsLikeAccounts(t *testing.T) {
+	f := newAuthServiceFixture(nil, core.WithLockout(memory.NewLoginAttemptRepository(), domain.LockoutPolicy{
+		MaxAttempts:  2,
+		BaseDuration: time.Minute,
+		MaxDuration:  time.Hour,
+		ResetAfter:   time.Hour,
+	}))
+	service := f.service
+	ctx := context.Background()
+
+	_, err := service.Login(ctx, "nobody@example.com", "password123")
+	assert.ErrorIs(t, err, domain.ErrInvalidCredentials)
+
+	// Case and whitespace do not give a fresh set of attempts
+	_, err = service.Login(ctx, " Nobody@Example.com ", "password123")
+	assert.ErrorIs(t, err, domain.ErrAccountLocked)
+
+	_, err = service.Login(ctx, "nobody@example.com", "password123")
+	assert.ErrorIs(t, err, domain.ErrAccountLocked)
+
+	for _, event := range f.publisher.Events() {
+		assert.NotEqual(t, domain.EventAccountLocked, event.Name, "no account to report")
+	}
+}
@@ -0,0 +1,94 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"testing"
+
+	grpcadapter "github.com/natrayanp/GoMicro/auth-service/internal/adapters/grpc"
+	"github.com/natrayanp/GoMicro/auth-service/internal/config"
+	"github.com/natrayanp/GoMicro/auth-service/internal/logging"
+	pb "github.com/natrayanp/GoMicro/auth-service/proto/auth/v1"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestLogging_RedactsSecretsAndHashesEmails(t *testing.T) {
+	logger, buf := newTestLogger(t, "info")
+
+	logger.Info("test",
+		"email", "Alice@Example.com",
+		"password", "hunter2",
+		"refresh_token", "abc.def",
+		slog.Group("request", "new_password", "hunter3", "user_email", "alice@example.com"),
+		"user_id", "user-1",
+	)
+
+	out := buf.buf.String()
+	assert.NotContains(t, out, "hunter")
+	assert.NotContains(t, out, "abc.def")
+	assert.NotContains(t, out, "xample.com")
+
+	entry := buf.lines(t)[0]
+	assert.Equal(t, logging.HashEmail("alice@example.com"), entry["email"])
+	assert.Equal(t, logging.Redacted, entry["password"])
+	assert.Equal(t, logging.Redacted, entry["refresh_token"])
+	assert.Equal(t, "user-1", entry["user_id"])
+
+	group := entry["request"].(map[string]any)
+	assert.Equal(t, logging.Redacted, group["new_password"])
+	assert.Equal(t, entry["email"], group["user_email"], "same email, same hash")
+}
+
+func TestLogging_New(t *testing.T) {
+	_, err := logging.New(&bytes.Buffer{}, &config.LogConfig{Level: "verbose", Format: "json"})
+	assert.Error(t, err)
+
+	_, err = logging.New(&bytes.Buffer{}, &config.LogConfig{Level: "info", Format: "xml"})
+	assert.Error(t, err)
+
+	logger, err := logging.New(&bytes.Buffer{}, &config.LogConfig{Level: "warn", Format: "text"})
+	require.NoError(t, err)
+	assert.False(t, logger.Enabled(context.Background(), slog.LevelInfo))
+}
+
+func TestGrpcServer_LogsRequests(t *testing.T) {
+	logger, buf := newTestLogger(t, "debug")
+	f := newAuthServiceFixture(nil)
+	client := startGrpcServer(t, f.service, grpcadapter.WithLogger(logger))
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "x-request-id", "req-123")
+	var header metadata.MD
+	_, err := client.Register(ctx, &pb.RegisterRequest{Email: "test@example.com", Password: "password123"}, grpc.Header(&header))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"req-123"}, header.Get("x-request-id"))
+
+	// Without an incoming ID one is generated
+	_, err = client.Login(context.Background(), &pb.LoginRequest{Email: "test@example.com", Password: "wrong"}, grpc.Header(&header))
+	require.Error(t, err)
+	assert.NotEmpty(t, header.Get("x-request-id"))
+	assert.NotEqual(t, "req-123", header.Get("x-request-id")[0])
+
+	assert.NotContains(t, buf.buf.String(), "test@example.com")
+	assert.NotContains(t, buf.buf.String(), "password123")
+
+	var requests []map[string]any
+	for _, entry := range buf.lines(t) {
+		if entry["msg"] == "grpc request" {
+			requests = append(requests, entry)
+		}
+	}
+	require.Len(t, requests, 2)
+
+	assert.Equal(t, "req-123", requests[0]["request_id"])
+	assert.Equal(t, "/auth.v1.AuthService/Register", requests[0]["method"])
+	assert.Equal(t, "OK", requests[0]["code"])
+	assert.Contains(t, requests[0], "duration_ms")
+
+	assert.Equal(t, header.Get("x-request-id")[0], requests[1]["request_id"])
+	assert.Equal(t, "Unauthenticated", requests[1]["code"])
+}
@@ -0,0 +1,104 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/natrayanp/GoMicro/auth-service/internal/domain"
+	"github.com/natrayanp/GoMicro/auth-service/internal/ports"
+	"github.com/natrayanp/GoMicro/auth-service/internal/storage/memory"
+	"github.com/natrayanp/GoMicro/auth-service/internal/storage/storagetest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryUserRepository(t *testing.T) {
+	storagetest.RunUserRepositoryTests(t, func(t *testing.T) ports.UserRepository {
+		return memory.NewUserRepository()
+	})
+}
+
+func TestMemoryTokenRepository(t *testing.T) {
+	storagetest.RunTokenRepositoryTests(t, func(t *testing.T) (ports.UserRepository, ports.TokenRepository) {
+		return memory.NewUserRepository(), memory.NewTokenRepository()
+	})
+}
+
+func TestMemoryPasswordHistoryRepository(t *testing.T) {
+	storagetest.RunPasswordHistoryRepositoryTests(t, func(t *testing.T) (ports.UserRepository, ports.PasswordHistoryRepository) {
+		return memory.NewUserRepository(), memory.NewPasswordHistoryRepository()
+	})
+}
+
+func TestMemoryLoginAttemptRepository(t *testing.T) {
+	storagetest.RunLoginAttemptRepositoryTests(t, func(t *testing.T) ports.LoginAttemptRepository {
+		return memory.NewLoginAttemptRepository()
+	})
+}
+
+func TestMemoryAccessTokenDenylistRepository(t *testing.T) {
+	storagetest.RunAccessTokenDenylistRepositoryTests(t, func(t *testing.T) (ports.UserRepository, ports.AccessTokenDenylistRepository) {
+		return memory.NewUserRepository(), memory.NewAccessTokenDenylistRepository()
+	})
+}
+
+func TestMemoryUnitOfWork_RollbackKeepsConcurrentWrites(t *testing.T) {
+	users := memory.NewUserRepository()
+	tokens := memory.NewTokenRepository()
+	history := memory.NewPasswordHistoryRepository()
+	uow := memory.NewUnitOfWork(ports.TxRepositories{Users: users, Tokens: tokens, PasswordHistory: history})
+	ctx := context.Background()
+
+	alice := &domain.User{Email: "alice@example.com", PasswordHash: "alice-hash"}
+	require.NoError(t, users.CreateUser(ctx, alice))
+	aliceToken := &domain.RefreshToken{UserID: alice.ID, TokenHash: "alice-token", ExpiresAt: time.Now().Add(time.Hour)}
+	require.NoError(t, tokens.CreateRefreshToken(ctx, aliceToken))
+
+	bob := &domain.User{Email: "bob@example.com", PasswordHash: "bob-hash"}
+	errFailed := errors.New("failed")
+
+	err := uow.WithinTx(ctx, func(txCtx context.Context, repos ports.TxRepositories) error {
+		require.NoError(t, repos.Users.UpdateUserPassword(txCtx, alice.ID, "alice-new-hash"))
+		require.NoError(t, repos.PasswordHistory.AddPasswordHistory(txCtx, alice.ID, "alice-hash", 5))
+		require.NoError(t, repos.Tokens.RevokeAllUserTokens(txCtx, alice.ID))
+
+		// Another request writes to the same repositories meanwhile
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			assert.NoError(t, users.CreateUser(ctx, bob))
+			assert.NoError(t, tokens.CreateRefreshToken(ctx, &domain.RefreshToken{
+				UserID: bob.ID, TokenHash: "bob-token", ExpiresAt: time.Now().Add(time.Hour),
+			}))
+			assert.NoError(t, history.AddPasswordHistory(ctx, bob.ID, "bob-old-hash", 5))
+		}()
+		<-done
+
+		return errFailed
+	})
+	require.ErrorIs(t, err, errFailed)
+
+	// The transaction's own writes are undone
+	stored, err := users.GetUserByID(ctx, alice.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "alice-hash", stored.PasswordHash)
+	aliceHistory, err := history.GetPasswordHistory(ctx, alice.ID, 5)
+	require.NoError(t, err)
+	assert.Empty(t, aliceHistory)
+	valid, err := tokens.GetValidRefreshTokens(ctx, alice.ID)
+	require.NoError(t, err)
+	assert.Len(t, valid, 1)
+
+	// The concurrent writes survive
+	_, err = users.GetUserByEmail(ctx, "bob@example.com")
+	assert.NoError(t, err)
+	valid, err = tokens.GetValidRefreshTokens(ctx, bob.ID)
+	require.NoError(t, err)
+	assert.Len(t, valid, 1)
+	bobHistory, err := history.GetPasswordHistory(ctx, bob.ID, 5)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"bob-old-hash"}, bobHistory)
+}
@@ -0,0 +1,45 @@
+package tests
+
+import (
+	"context"
+	"testing"
+
+	grpcadapter "github.com/natrayanp/GoMicro/auth-service/internal/adapters/grpc"
+	"github.com/natrayanp/GoMicro/auth-service/internal/adapters/metrics"
+	"github.com/natrayanp/GoMicro/auth-service/internal/core"
+	pb "github.com/natrayanp/GoMicro/auth-service/proto/auth/v1"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrometheus_DomainAndRPCMetrics(t *testing.T) {
+	m := metrics.NewPrometheus()
+	service := newAuthServiceFixture(nil, core.WithMetrics(m)).service
+
+	client := startGrpcServer(t, service, grpcadapter.WithMetrics(m))
+	ctx := context.Background()
+
+	_, err := client.Register(ctx, &pb.RegisterRequest{Email: "test@example.com", Password: "password123"})
+	require.NoError(t, err)
+
+	_, err = client.Login(ctx, &pb.LoginRequest{Email: "test@example.com", Password: "wrong-password"})
+	require.Error(t, err)
+
+	login, err := client.Login(ctx, &pb.LoginRequest{Email: "test@example.com", Password: "password123"})
+	require.NoError(t, err)
+
+	_, err = client.Logout(ctx, &pb.LogoutRequest{RefreshToken: login.RefreshToken})
+	require.NoError(t, err)
+
+	body := scrapeMetrics(t, m)
+
+	assert.Contains(t, body, `auth_registrations_total 1`)
+	assert.Contains(t, body, `auth_logins_total{result="success"} 1`)
+	assert.Contains(t, body, `auth_logins_total{result="invalid_credentials"} 1`)
+	assert.Contains(t, body, `auth_token_revocations_total{reason="logout"} 1`)
+	assert.Contains(t, body, `auth_grpc_requests_total{code="OK",method="/auth.v1.AuthService/Login"} 1`)
+	assert.Contains(t, body, `auth_grpc_requests_total{code="Unauthenticated",method="/auth.v1.AuthService/Login"} 1`)
+	assert.Contains(t, body, `auth_grpc_request_duration_seconds_count{code="OK",method="/auth.v1.AuthService/Register"} 1`)
+	assert.Contains(t, body, `go_goroutines`)
+}
@@ -0,0 +1,26 @@
+package tests
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/natrayanp/GoMicro/auth-service/internal/storage/postgres"
+	"github.com/natrayanp/GoMicro/auth-service/sql/schema"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEmbeddedMigrations(t *testing.T) {
+	migrator, err := postgres.NewMigrator(nil, schema.FS)
+	assert.NoError(t, err)
+
+	migrations := migrator.Migrations()
+	assert.NotEmpty(t, migrations)
+
+	// Versions are contiguous from 1 and every migration has an Up section
+	for i, migration := range migrations {
+		assert.Equal(t, int64(i+1), migration.Version, migration.Name)
+		assert.NotEmpty(t, strings.TrimSpace(migration.Up), migration.Name)
+		assert.NotContains(t, migration.Up, "+goose Down", migration.Name)
+	}
+}
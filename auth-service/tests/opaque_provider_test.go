@@ -0,0 +1,70 @@
+package tests
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/natrayanp/GoMicro/auth-service/internal/auth/jwt"
+	"github.com/natrayanp/GoMicro/auth-service/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOpaqueProvider_GenerateTokenPair(t *testing.T) {
+	provider := newOpaqueProvider()
+
+	tokenPair, err := provider.GenerateTokenPair(domain.TokenGrant{UserID: "user123"})
+	assert.NoError(t, err)
+
+	// Access tokens are still JWTs
+	claims, err := provider.ValidateToken(tokenPair.AccessToken)
+	assert.NoError(t, err)
+	assert.Equal(t, "user123", claims.Subject)
+	assert.Equal(t, domain.TokenTypeAccess, claims.Type)
+
+	// Refresh tokens are selector.verifier and carry no user ID
+	selector, verifier, ok := strings.Cut(tokenPair.RefreshToken, ".")
+	assert.True(t, ok)
+	assert.Len(t, selector, 22)
+	assert.Len(t, verifier, 43)
+	assert.NotContains(t, tokenPair.RefreshToken, "user123")
+	assert.WithinDuration(t, time.Now().Add(7*24*time.Hour), tokenPair.RefreshExpiresAt, time.Minute)
+
+	lookup, err := provider.ParseRefreshToken(tokenPair.RefreshToken)
+	assert.NoError(t, err)
+	assert.Equal(t, selector, lookup.Selector)
+	assert.Equal(t, provider.HashRefreshToken(tokenPair.RefreshToken), lookup.TokenHash)
+	assert.NotContains(t, lookup.TokenHash, verifier)
+
+	// Opaque refresh tokens are never accepted as JWTs
+	_, err = provider.ValidateToken(tokenPair.RefreshToken)
+	assert.Error(t, err)
+}
+
+func TestOpaqueProvider_ParseRefreshTokenRejectsMalformed(t *testing.T) {
+	provider := newOpaqueProvider()
+
+	access, err := provider.GenerateAccessToken("user123")
+	assert.NoError(t, err)
+
+	for _, token := range []string{"", "no-separator", "short.token", access} {
+		_, err := provider.ParseRefreshToken(token)
+		assert.ErrorIs(t, err, domain.ErrInvalidToken, token)
+	}
+}
+
+func TestJWTProvider_ParseRefreshTokenRejectsAccessToken(t *testing.T) {
+	provider := jwt.NewJWTProvider("test-secret-key", 15*time.Minute, 7*24*time.Hour)
+
+	tokenPair, err := provider.GenerateTokenPair(domain.TokenGrant{UserID: "user123"})
+	assert.NoError(t, err)
+
+	lookup, err := provider.ParseRefreshToken(tokenPair.RefreshToken)
+	assert.NoError(t, err)
+	assert.Empty(t, lookup.Selector)
+	assert.Equal(t, provider.HashRefreshToken(tokenPair.RefreshToken), lookup.TokenHash)
+
+	_, err = provider.ParseRefreshToken(tokenPair.AccessToken)
+	assert.ErrorIs(t, err, domain.ErrWrongTokenType)
+}
@@ -0,0 +1,122 @@
+package tests
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/natrayanp/GoMicro/auth-service/internal/auth/password"
+	"github.com/natrayanp/GoMicro/auth-service/internal/core"
+	"github.com/natrayanp/GoMicro/auth-service/internal/domain"
+	"github.com/natrayanp/GoMicro/auth-service/internal/ports"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Cheap parameters keep the tests fast
+var testArgon2id = password.Argon2id{Memory: 64, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32}
+
+func TestPasswordHasher_Argon2id(t *testing.T) {
+	hasher := password.NewHasher(testArgon2id)
+
+	hash, err := hasher.Hash("password123")
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(hash, "$argon2id$v=19$m=64,t=1,p=1$"), hash)
+
+	ok, err := hasher.Verify("password123", hash)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	ok, err = hasher.Verify("wrong-password", hash)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	// Salted: the same password never hashes the same twice
+	other, err := hasher.Hash("password123")
+	assert.NoError(t, err)
+	assert.NotEqual(t, hash, other)
+
+	assert.False(t, hasher.NeedsRehash(hash))
+}
+
+func TestPasswordHasher_Bcrypt(t *testing.T) {
+	hasher := password.NewHasher(password.Bcrypt{Cost: 4})
+
+	hash, err := hasher.Hash("password123")
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(hash, "$2a$04$"), hash)
+
+	ok, err := hasher.Verify("password123", hash)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	ok, err = hasher.Verify("wrong-password", hash)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	assert.False(t, hasher.NeedsRehash(hash))
+}
+
+func TestPasswordHasher_NeedsRehash(t *testing.T) {
+	bcryptHash, err := password.NewHasher(password.Bcrypt{Cost: 4}).Hash("password123")
+	assert.NoError(t, err)
+
+	argonHash, err := password.NewHasher(testArgon2id).Hash("password123")
+	assert.NoError(t, err)
+
+	// Every hasher verifies both algorithms
+	argon := password.NewHasher(testArgon2id)
+	ok, err := argon.Verify("password123", bcryptHash)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	// Other algorithm
+	assert.True(t, argon.NeedsRehash(bcryptHash))
+	assert.True(t, password.NewHasher(password.Bcrypt{Cost: 4}).NeedsRehash(argonHash))
+
+	// Same algorithm, other cost
+	assert.True(t, password.NewHasher(password.Bcrypt{Cost: 5}).NeedsRehash(bcryptHash))
+	stronger := testArgon2id
+	stronger.Iterations = 2
+	assert.True(t, password.NewHasher(stronger).NeedsRehash(argonHash))
+}
+
+func TestPasswordHasher_MalformedHash(t *testing.T) {
+	hasher := password.NewHasher(testArgon2id)
+
+	for _, hash := range []string{"", "plaintext", "$argon2id$v=19$m=64,t=1,p=1$!!!$abc", "$argon2id$v=18$m=64,t=1,p=1$c2FsdA$aGFzaA"} {
+		ok, err := hasher.Verify("password123", hash)
+		assert.Error(t, err, hash)
+		assert.False(t, ok, hash)
+	}
+}
+
+func TestAuthService_LoginRehashesOutdatedPassword(t *testing.T) {
+	ctx := context.Background()
+
+	// Registered while the service still used bcrypt
+	legacy := newAuthServiceFixture(nil,
+		core.WithPasswordHasher(password.NewHasher(password.Bcrypt{Cost: 4})),
+	)
+	user, err := legacy.service.Register(ctx, "test@example.com", "password123")
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(user.PasswordHash, "$2a$"))
+
+	service := newAuthServiceFixtureOn(ports.TxRepositories{Users: legacy.users, Tokens: legacy.tokens}, nil,
+		core.WithPasswordHasher(password.NewHasher(testArgon2id)),
+	).service
+
+	_, err = service.Login(ctx, "test@example.com", "password123")
+	assert.NoError(t, err)
+
+	stored, err := legacy.users.GetUserByID(ctx, user.ID)
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(stored.PasswordHash, "$argon2id$"), stored.PasswordHash)
+
+	// The upgraded hash still logs in, and a wrong password still fails
+	_, err = service.Login(ctx, "test@example.com", "password123")
+	assert.NoError(t, err)
+
+	_, err = service.Login(ctx, "test@example.com", "wrong-password")
+	assert.ErrorIs(t, err, domain.ErrInvalidCredentials)
+}
@@ -0,0 +1,176 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/natrayanp/GoMicro/auth-service/internal/auth/password"
+	"github.com/natrayanp/GoMicro/auth-service/internal/config"
+	"github.com/natrayanp/GoMicro/auth-service/internal/core"
+	"github.com/natrayanp/GoMicro/auth-service/internal/domain"
+	"github.com/natrayanp/GoMicro/auth-service/internal/ports"
+	"github.com/natrayanp/GoMicro/auth-service/internal/storage/memory"
+	pb "github.com/natrayanp/GoMicro/auth-service/proto/auth/v1"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+)
+
+func policyRules(err error) []domain.PasswordRule {
+	var policyErr *domain.PasswordPolicyError
+	if !errors.As(err, &policyErr) {
+		return nil
+	}
+
+	rules := make([]domain.PasswordRule, len(policyErr.Violations))
+	for i, v := range policyErr.Violations {
+		rules[i] = v.Rule
+		if v.Message == "" {
+			return nil
+		}
+	}
+	return rules
+}
+
+func TestPasswordPolicy_Rules(t *testing.T) {
+	policy := domain.PasswordPolicy{
+		MinLength:        10,
+		MaxLength:        20,
+		RequireUppercase: true,
+		RequireLowercase: true,
+		RequireDigit:     true,
+		RequireSymbol:    true,
+		DisallowEmail:    true,
+	}
+	email := "alice.smith@example.com"
+
+	assert.NoError(t, policy.Check("Correct-Horse-9", email))
+
+	tests := map[string][]domain.PasswordRule{
+		"Sh0rt!":                  {domain.PasswordRuleMinLength},
+		"Way-Too-Long-Password-1": {domain.PasswordRuleMaxLength},
+		"lowercase-only-9":        {domain.PasswordRuleUppercase},
+		"UPPERCASE-ONLY-9":        {domain.PasswordRuleLowercase},
+		"No-Digits-Here":          {domain.PasswordRuleDigit},
+		"NoSymbolsHere9":          {domain.PasswordRuleSymbol},
+		"My-Alice.Smith-1":        {domain.PasswordRuleContainsEmail},
+		"short":                   {domain.PasswordRuleMinLength, domain.PasswordRuleUppercase, domain.PasswordRuleDigit, domain.PasswordRuleSymbol},
+	}
+
+	for pw, want := range tests {
+		err := policy.Check(pw, email)
+		assert.ErrorIs(t, err, domain.ErrPasswordPolicy, pw)
+		assert.Equal(t, want, policyRules(err), pw)
+	}
+}
+
+func TestPasswordPolicy_TooShortStillMatchesLegacyError(t *testing.T) {
+	err := domain.DefaultPasswordPolicy.Check("short", "alice@example.com")
+	assert.ErrorIs(t, err, domain.ErrPasswordTooShort)
+
+	err = domain.PasswordPolicy{MinLength: 1, RequireDigit: true}.Check("nodigits", "")
+	assert.Error(t, err)
+	assert.NotErrorIs(t, err, domain.ErrPasswordTooShort)
+}
+
+func TestPasswordPolicy_CommonPasswords(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "common.txt")
+	assert.NoError(t, os.WriteFile(path, []byte("# top passwords\nPassword123\n\nqwertyuiop\n"), 0o600))
+
+	common, err := password.LoadCommonPasswords(path)
+	assert.NoError(t, err)
+	assert.Len(t, common, 2)
+
+	policy := password.NewPolicy(domain.DefaultPasswordPolicy, common)
+
+	err = policy.Check("password123", "alice@example.com")
+	assert.Equal(t, []domain.PasswordRule{domain.PasswordRuleCommon}, policyRules(err))
+
+	assert.NoError(t, policy.Check("a-much-better-password", "alice@example.com"))
+
+	_, err = password.LoadCommonPasswords(filepath.Join(t.TempDir(), "missing.txt"))
+	assert.Error(t, err)
+}
+
+func TestAuthService_PasswordPolicyOnRegister(t *testing.T) {
+	service := newAuthServiceFixture(nil,
+		core.WithPasswordPolicy(domain.PasswordPolicy{MinLength: 8, RequireDigit: true, DisallowEmail: true}),
+	).service
+	ctx := context.Background()
+
+	_, err := service.Register(ctx, "alice@example.com", "alice-password-1")
+	assert.Equal(t, []domain.PasswordRule{domain.PasswordRuleContainsEmail}, policyRules(err))
+
+	_, err = service.Register(ctx, "alice@example.com", "no-digits-here")
+	assert.Equal(t, []domain.PasswordRule{domain.PasswordRuleDigit}, policyRules(err))
+
+	_, err = service.Register(ctx, "alice@example.com", "good-password-1")
+	assert.NoError(t, err)
+}
+
+func TestAuthService_PasswordHistory(t *testing.T) {
+	history := memory.NewPasswordHistoryRepository()
+	service := newAuthServiceFixtureOn(ports.TxRepositories{PasswordHistory: history}, nil,
+		core.WithPasswordHasher(password.NewHasher(password.Bcrypt{Cost: 4})),
+		core.WithPasswordHistory(history, 1),
+	).service
+	ctx := context.Background()
+
+	user, err := service.Register(ctx, "alice@example.com", "password-zero")
+	assert.NoError(t, err)
+
+	assert.NoError(t, service.UpdateUserPassword(ctx, user.ID, "password-one"))
+
+	// Neither the current nor the previous password may come back
+	err = service.UpdateUserPassword(ctx, user.ID, "password-one")
+	assert.Equal(t, []domain.PasswordRule{domain.PasswordRuleReused}, policyRules(err))
+
+	err = service.UpdateUserPassword(ctx, user.ID, "password-zero")
+	assert.Equal(t, []domain.PasswordRule{domain.PasswordRuleReused}, policyRules(err))
+
+	// With a history of one, the oldest password ages out
+	assert.NoError(t, service.UpdateUserPassword(ctx, user.ID, "password-two"))
+	assert.NoError(t, service.UpdateUserPassword(ctx, user.ID, "password-zero"))
+}
+
+func TestGrpcServer_BcryptRejectsPasswordsOver72Bytes(t *testing.T) {
+	// 40 characters, well under the length limit, but 80 bytes
+	long := strings.Repeat("é", 40)
+	require.Len(t, long, 80)
+
+	policy, err := password.NewPolicyFromConfig(&config.PasswordConfig{Algorithm: "bcrypt", MinLength: 8, MaxLength: 128})
+	require.NoError(t, err)
+	hasher := core.WithPasswordHasher(password.NewHasher(password.Bcrypt{Cost: 4}))
+
+	tests := []struct {
+		name string
+		opts []core.Option
+	}{
+		{"policy", []core.Option{hasher, core.WithPasswordPolicy(policy)}},
+		{"hasher only", []core.Option{hasher}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newAuthServiceFixture(nil, tt.opts...)
+			client := startGrpcServer(t, f.service)
+			ctx := context.Background()
+
+			_, err := client.Register(ctx, &pb.RegisterRequest{Email: "alice@example.com", Password: long})
+			assertReason(t, err, codes.InvalidArgument, domain.ReasonPasswordPolicy)
+
+			_, err = f.service.Register(ctx, "alice@example.com", long)
+			assert.Equal(t, []domain.PasswordRule{domain.PasswordRuleMaxLength}, policyRules(err))
+
+			user, err := f.service.Register(ctx, "alice@example.com", "password123")
+			require.NoError(t, err)
+			err = f.service.UpdateUserPassword(ctx, user.ID, long)
+			assert.Equal(t, []domain.PasswordRule{domain.PasswordRuleMaxLength}, policyRules(err))
+		})
+	}
+}
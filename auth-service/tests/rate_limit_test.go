@@ -0,0 +1,168 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	grpcadapter "github.com/natrayanp/GoMicro/auth-service/internal/adapters/grpc"
+	"github.com/natrayanp/GoMicro/auth-service/internal/config"
+	"github.com/natrayanp/GoMicro/auth-service/internal/ratelimit"
+	pb "github.com/natrayanp/GoMicro/auth-service/proto/auth/v1"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestParseRules(t *testing.T) {
+	rules, err := ratelimit.ParseRules("Login=ip:20/1m, Login=email:5/m,*=principal:10/1s")
+	require.NoError(t, err)
+	require.Len(t, rules, 3)
+
+	assert.Equal(t, "Login", rules[0].Method)
+	assert.Equal(t, ratelimit.KeyIP, rules[0].Key)
+	assert.Equal(t, 20, rules[0].Limit.Burst)
+	assert.InDelta(t, 20.0/60, rules[0].Limit.Rate, 1e-9)
+
+	assert.Equal(t, ratelimit.KeyEmail, rules[1].Key)
+	assert.InDelta(t, 5.0/60, rules[1].Limit.Rate, 1e-9)
+
+	assert.Equal(t, "*", rules[2].Method)
+	assert.Equal(t, ratelimit.KeyPrincipal, rules[2].Key)
+
+	for _, invalid := range []string{"Login", "Login=ip", "Login=user:5/1m", "Login=ip:0/1m", "Login=ip:5/soon"} {
+		_, err := ratelimit.ParseRules(invalid)
+		assert.Error(t, err, invalid)
+	}
+
+	rules, err = ratelimit.ParseRules("")
+	assert.NoError(t, err)
+	assert.Empty(t, rules)
+}
+
+func TestMemoryStore_TokenBucket(t *testing.T) {
+	store := ratelimit.NewMemoryStore()
+	ctx := context.Background()
+	limit := ratelimit.Limit{Rate: 20, Burst: 2} // one token every 50ms
+
+	for i := 0; i < 2; i++ {
+		decision, err := store.Take(ctx, "key", limit)
+		require.NoError(t, err)
+		assert.True(t, decision.Allowed)
+	}
+
+	decision, err := store.Take(ctx, "key", limit)
+	require.NoError(t, err)
+	assert.False(t, decision.Allowed)
+	assert.Greater(t, decision.RetryAfter, time.Duration(0))
+	assert.LessOrEqual(t, decision.RetryAfter, 50*time.Millisecond)
+
+	// Other keys have their own bucket
+	decision, err = store.Take(ctx, "other", limit)
+	require.NoError(t, err)
+	assert.True(t, decision.Allowed)
+
+	time.Sleep(60 * time.Millisecond)
+
+	decision, err = store.Take(ctx, "key", limit)
+	require.NoError(t, err)
+	assert.True(t, decision.Allowed)
+}
+
+func TestLimiter_Check(t *testing.T) {
+	limiter := ratelimit.NewLimiter(ratelimit.NewMemoryStore(), []ratelimit.Rule{
+		{Method: "Login", Key: ratelimit.KeyEmail, Limit: ratelimit.Limit{Rate: 0.001, Burst: 1}},
+		{Method: "*", Key: ratelimit.KeyPrincipal, Limit: ratelimit.Limit{Rate: 0.001, Burst: 3}},
+	})
+	ctx := context.Background()
+
+	decision, err := limiter.Check(ctx, "Login", ratelimit.Keys{IP: "10.0.0.1", Email: "Alice@Example.com"})
+	require.NoError(t, err)
+	assert.True(t, decision.Allowed)
+
+	// Emails are compared case-insensitively, whatever the IP
+	decision, err = limiter.Check(ctx, "Login", ratelimit.Keys{IP: "10.0.0.2", Email: "alice@example.com"})
+	require.NoError(t, err)
+	assert.False(t, decision.Allowed)
+
+	// Without a principal the wildcard rule falls back to the IP, which
+	// 10.0.0.1 has used once already
+	for i := 0; i < 2; i++ {
+		decision, err = limiter.Check(ctx, "GetUser", ratelimit.Keys{IP: "10.0.0.1"})
+		require.NoError(t, err)
+		assert.True(t, decision.Allowed)
+	}
+	decision, err = limiter.Check(ctx, "GetUser", ratelimit.Keys{IP: "10.0.0.1"})
+	require.NoError(t, err)
+	assert.False(t, decision.Allowed)
+
+	// An authenticated user on the same IP has a separate bucket
+	decision, err = limiter.Check(ctx, "GetUser", ratelimit.Keys{IP: "10.0.0.1", Principal: "user-1"})
+	require.NoError(t, err)
+	assert.True(t, decision.Allowed)
+}
+
+func TestLimiter_CheckDenialCostsNothing(t *testing.T) {
+	limiter := ratelimit.NewLimiter(ratelimit.NewMemoryStore(), []ratelimit.Rule{
+		{Method: "Login", Key: ratelimit.KeyIP, Limit: ratelimit.Limit{Rate: 0.001, Burst: 2}},
+		{Method: "Login", Key: ratelimit.KeyEmail, Limit: ratelimit.Limit{Rate: 0.001, Burst: 1}},
+	})
+	ctx := context.Background()
+
+	decision, err := limiter.Check(ctx, "Login", ratelimit.Keys{IP: "10.0.0.1", Email: "alice@example.com"})
+	require.NoError(t, err)
+	assert.True(t, decision.Allowed)
+
+	// The email rule denies these, so the IP bucket keeps its last token
+	for i := 0; i < 3; i++ {
+		decision, err = limiter.Check(ctx, "Login", ratelimit.Keys{IP: "10.0.0.1", Email: "alice@example.com"})
+		require.NoError(t, err)
+		assert.False(t, decision.Allowed)
+	}
+
+	decision, err = limiter.Check(ctx, "Login", ratelimit.Keys{IP: "10.0.0.1", Email: "bob@example.com"})
+	require.NoError(t, err)
+	assert.True(t, decision.Allowed)
+}
+
+func TestGrpcServer_RateLimitsLogin(t *testing.T) {
+	f := newAuthServiceFixture(nil)
+
+	limiter, err := ratelimit.NewLimiterFromConfig(&config.RateLimitConfig{
+		Enabled: true,
+		Rules:   "Login=email:2/1h",
+	}, ratelimit.NewMemoryStore())
+	require.NoError(t, err)
+
+	client := startGrpcServer(t, f.service, grpcadapter.WithRateLimiter(limiter))
+	ctx := context.Background()
+	req := &pb.LoginRequest{Email: "nobody@example.com", Password: "password123"}
+
+	for i := 0; i < 2; i++ {
+		_, err := client.Login(ctx, req)
+		assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	}
+
+	var trailer metadata.MD
+	_, err = client.Login(ctx, req, grpc.Trailer(&trailer))
+	st := status.Convert(err)
+	assert.Equal(t, codes.ResourceExhausted, st.Code())
+	assert.NotEmpty(t, trailer.Get("retry-after"))
+
+	retryInfo, ok := statusDetail[*errdetails.RetryInfo](st)
+	require.True(t, ok)
+	assert.Greater(t, retryInfo.RetryDelay.AsDuration(), 29*time.Minute)
+
+	info, ok := statusDetail[*errdetails.ErrorInfo](st)
+	require.True(t, ok)
+	assert.Equal(t, grpcadapter.ReasonRateLimited, info.Reason)
+
+	// Other emails are unaffected
+	_, err = client.Login(ctx, &pb.LoginRequest{Email: "other@example.com", Password: "password123"})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
@@ -0,0 +1,92 @@
+package tests
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	pb "github.com/natrayanp/GoMicro/auth-service/proto/auth/v1"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc/metadata"
+)
+
+var (
+	spanRecorderOnce sync.Once
+	spanRecorder     *tracetest.SpanRecorder
+)
+
+// recordSpans installs a global provider that keeps every span in memory.
+// Tracers created before the first call delegate to the first provider
+// set, so it is installed once and shared between tests.
+func recordSpans() *tracetest.SpanRecorder {
+	spanRecorderOnce.Do(func() {
+		spanRecorder = tracetest.NewSpanRecorder()
+		otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spanRecorder)))
+		otel.SetTextMapPropagator(propagation.TraceContext{})
+	})
+	return spanRecorder
+}
+
+func spansInTrace(recorder *tracetest.SpanRecorder, traceID trace.TraceID) map[string]sdktrace.ReadOnlySpan {
+	spans := make(map[string]sdktrace.ReadOnlySpan)
+	for _, span := range recorder.Ended() {
+		if span.SpanContext().TraceID() == traceID {
+			spans[span.Name()] = span
+		}
+	}
+	return spans
+}
+
+func TestTracing_LoginContinuesCallerTrace(t *testing.T) {
+	recorder := recordSpans()
+	f := newAuthServiceFixture(nil)
+	client := startGrpcServer(t, f.service)
+
+	_, err := f.service.Register(context.Background(), "test@example.com", "password123")
+	require.NoError(t, err)
+
+	traceID, _ := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	callerSpanID, _ := trace.SpanIDFromHex("00f067aa0ba902b7")
+	ctx := metadata.AppendToOutgoingContext(context.Background(),
+		"traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+
+	_, err = client.Login(ctx, &pb.LoginRequest{Email: "test@example.com", Password: "password123"})
+	require.NoError(t, err)
+
+	spans := spansInTrace(recorder, traceID)
+
+	server, ok := spans["auth.v1.AuthService/Login"]
+	require.True(t, ok, "server span")
+	assert.Equal(t, trace.SpanKindServer, server.SpanKind())
+	assert.Equal(t, callerSpanID, server.Parent().SpanID())
+
+	login, ok := spans["AuthService.Login"]
+	require.True(t, ok, "service span")
+	assert.Equal(t, server.SpanContext().SpanID(), login.Parent().SpanID())
+
+	verify, ok := spans["PasswordHasher.Verify"]
+	require.True(t, ok, "hash span")
+	assert.Equal(t, login.SpanContext().SpanID(), verify.Parent().SpanID())
+}
+
+func TestTracing_ExpectedErrorsDoNotFailSpans(t *testing.T) {
+	recorder := recordSpans()
+	f := newAuthServiceFixture(nil)
+
+	ctx, parent := otel.Tracer("test").Start(context.Background(), "test")
+	_, err := f.service.Login(ctx, "nobody@example.com", "password123")
+	parent.End()
+	require.Error(t, err)
+
+	login, ok := spansInTrace(recorder, parent.SpanContext().TraceID())["AuthService.Login"]
+	require.True(t, ok)
+	assert.NotEqual(t, "Error", login.Status().Code.String())
+	assert.NotEmpty(t, login.Events(), "error is still recorded")
+}
@@ -0,0 +1,167 @@
+package tests
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/natrayanp/GoMicro/auth-service/internal/adapters/metrics"
+	"github.com/natrayanp/GoMicro/auth-service/internal/core"
+	"github.com/natrayanp/GoMicro/auth-service/internal/domain"
+	"github.com/natrayanp/GoMicro/auth-service/internal/ports"
+	"github.com/natrayanp/GoMicro/auth-service/internal/storage/memory"
+	"github.com/natrayanp/GoMicro/auth-service/internal/tokencache"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func cachedClaims(userID string, expiresIn time.Duration) *domain.TokenClaims {
+	return &domain.TokenClaims{
+		Subject:   userID,
+		Type:      domain.TokenTypeAccess,
+		ExpiresAt: time.Now().Add(expiresIn),
+	}
+}
+
+func TestLRU_EvictsLeastRecentlyUsed(t *testing.T) {
+	cache := tokencache.NewLRU(2, time.Minute)
+
+	cache.Add("a", cachedClaims("user-a", time.Hour), cache.Generation())
+	cache.Add("b", cachedClaims("user-b", time.Hour), cache.Generation())
+
+	// Touch a so b is the oldest
+	_, ok := cache.Get("a")
+	require.True(t, ok)
+
+	cache.Add("c", cachedClaims("user-c", time.Hour), cache.Generation())
+	assert.Equal(t, 2, cache.Len())
+
+	_, ok = cache.Get("b")
+	assert.False(t, ok)
+	claims, ok := cache.Get("a")
+	require.True(t, ok)
+	assert.Equal(t, "user-a", claims.Subject)
+}
+
+func TestLRU_TTLCappedAtTokenExpiry(t *testing.T) {
+	cache := tokencache.NewLRU(10, time.Hour)
+
+	cache.Add("short", cachedClaims("user-1", 50*time.Millisecond), cache.Generation())
+	cache.Add("expired", cachedClaims("user-1", -time.Second), cache.Generation())
+
+	_, ok := cache.Get("short")
+	assert.True(t, ok)
+	_, ok = cache.Get("expired")
+	assert.False(t, ok, "already expired tokens are not cached")
+
+	time.Sleep(60 * time.Millisecond)
+	_, ok = cache.Get("short")
+	assert.False(t, ok)
+	assert.Zero(t, cache.Len())
+}
+
+func TestLRU_InvalidateUser(t *testing.T) {
+	cache := tokencache.NewLRU(10, time.Minute)
+
+	cache.Add("a1", cachedClaims("user-a", time.Hour), cache.Generation())
+	cache.Add("a2", cachedClaims("user-a", time.Hour), cache.Generation())
+	cache.Add("b1", cachedClaims("user-b", time.Hour), cache.Generation())
+
+	cache.InvalidateUser("user-a")
+
+	_, ok := cache.Get("a1")
+	assert.False(t, ok)
+	_, ok = cache.Get("a2")
+	assert.False(t, ok)
+	_, ok = cache.Get("b1")
+	assert.True(t, ok)
+}
+
+func TestLRU_AddAfterInvalidateIsDropped(t *testing.T) {
+	cache := tokencache.NewLRU(10, time.Minute)
+
+	// A validation that started before the user was invalidated must not
+	// cache a token the invalidation meant to drop
+	gen := cache.Generation()
+	cache.InvalidateUser("user-a")
+	cache.Add("a1", cachedClaims("user-a", time.Hour), gen)
+
+	_, ok := cache.Get("a1")
+	assert.False(t, ok)
+
+	cache.Add("a1", cachedClaims("user-a", time.Hour), cache.Generation())
+	_, ok = cache.Get("a1")
+	assert.True(t, ok)
+}
+
+func TestAuthService_ValidationCache(t *testing.T) {
+	m := metrics.NewPrometheus()
+	cache := tokencache.NewLRU(100, time.Minute)
+	service := newAuthServiceFixture(nil,
+		core.WithMetrics(m),
+		core.WithValidationCache(cache),
+	).service
+	ctx := context.Background()
+
+	user, err := service.Register(ctx, "test@example.com", "password123")
+	require.NoError(t, err)
+	tokens, err := service.Login(ctx, "test@example.com", "password123")
+	require.NoError(t, err)
+
+	for i := 0; i < 3; i++ {
+		claims, err := service.ValidateToken(ctx, tokens.AccessToken)
+		require.NoError(t, err)
+		assert.Equal(t, user.ID, claims.Subject)
+	}
+
+	// Failures are never cached
+	_, err = service.ValidateToken(ctx, tokens.RefreshToken)
+	assert.ErrorIs(t, err, domain.ErrWrongTokenType)
+	assert.Equal(t, 1, cache.Len())
+
+	body := scrapeMetrics(t, m)
+	assert.Contains(t, body, `auth_validation_cache_lookups_total{result="hit"} 2`)
+	assert.Contains(t, body, `auth_validation_cache_lookups_total{result="miss"} 2`)
+
+	// Revoking the user's tokens drops their cached validations
+	require.NoError(t, service.RevokeAllUserTokens(ctx, user.ID))
+	assert.Zero(t, cache.Len())
+}
+
+// countingDenylist counts revocation checks, which hit the database in
+// production
+type countingDenylist struct {
+	ports.AccessTokenDenylistRepository
+	checks atomic.Int32
+}
+
+func (d *countingDenylist) IsAccessTokenRevoked(ctx context.Context, jti, userID string, issuedAt time.Time) (bool, error) {
+	d.checks.Add(1)
+	return d.AccessTokenDenylistRepository.IsAccessTokenRevoked(ctx, jti, userID, issuedAt)
+}
+
+func TestAuthService_ValidationCacheHitSkipsRepositories(t *testing.T) {
+	denylist := &countingDenylist{AccessTokenDenylistRepository: memory.NewAccessTokenDenylistRepository()}
+	service := newAuthServiceFixture(nil,
+		core.WithValidationCache(tokencache.NewLRU(100, time.Minute)),
+		core.WithAccessTokenDenylist(denylist, 15*time.Minute),
+	).service
+	ctx := context.Background()
+
+	_, err := service.Register(ctx, "test@example.com", "password123")
+	require.NoError(t, err)
+	tokens, err := service.Login(ctx, "test@example.com", "password123")
+	require.NoError(t, err)
+
+	_, err = service.ValidateToken(ctx, tokens.AccessToken)
+	require.NoError(t, err)
+	require.Equal(t, int32(1), denylist.checks.Load())
+
+	for i := 0; i < 3; i++ {
+		_, err = service.ValidateToken(ctx, tokens.AccessToken)
+		require.NoError(t, err)
+	}
+	assert.Equal(t, int32(1), denylist.checks.Load(), "cache hits must not reach the denylist")
+}